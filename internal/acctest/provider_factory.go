@@ -74,6 +74,19 @@ func testRemotes() map[string]provider_config.LxdRemote {
 	}
 }
 
+// ProviderWithConfigValidation returns a Terraform HCL provider block
+// configured from the default LXD remote and the given config validation
+// mode.
+func ProviderWithConfigValidation(mode string) string {
+	provider, err := provider_config.NewLxdProviderConfig("test", testRemotes(), testProviderRemoteName)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize provider: %v", err))
+	}
+
+	provider.SetConfigValidation(mode)
+	return provider.ToHCL()
+}
+
 // testProvider returns a LxdProviderConfig that is initialized with default
 // LXD config remote.
 func testProvider() *provider_config.LxdProviderConfig {
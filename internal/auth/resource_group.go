@@ -101,6 +101,17 @@ func (r *AuthGroupResource) Configure(_ context.Context, req resource.ConfigureR
 	r.provider = provider
 }
 
+// ModifyPlan checks whether the server supports authorization groups.
+func (r AuthGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip the checks on destroy or if the server is not reachable.
+	server := r.provider.PlanInstanceServer(ctx, req.Plan)
+	if server == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(common.CheckResourceExtensions(server, "lxd_auth_group", "access_management")...)
+}
+
 func (r AuthGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan AuthGroupModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -99,6 +99,27 @@ func (r *AuthIdentityResource) Configure(_ context.Context, req resource.Configu
 	r.provider = provider
 }
 
+// ModifyPlan checks whether the server supports identities and the selected authentication method.
+func (r AuthIdentityResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip the checks on destroy or if the server is not reachable.
+	server := r.provider.PlanInstanceServer(ctx, req.Plan)
+	if server == nil {
+		return
+	}
+
+	var plan AuthIdentityModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(common.CheckResourceExtensions(server, "lxd_auth_identity", "access_management")...)
+
+	if plan.AuthMethod.ValueString() == "bearer" {
+		resp.Diagnostics.Append(common.CheckAttributeExtensions(server, path.Root("auth_method"), "auth_bearer")...)
+	}
+}
+
 func (r AuthIdentityResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan AuthIdentityModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -0,0 +1,165 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Instance config keys holding cloud-init vendor data. The "user.*" key is
+// used by LXD servers without the "cloud_init" API extension.
+const (
+	VendorDataKey       = "cloud-init.vendor-data"
+	LegacyVendorDataKey = "user.vendor-data"
+)
+
+// AccessVendorDataKey returns the instance config key used to provision
+// the access user via cloud-init on the given server.
+func AccessVendorDataKey(server ExtensionServer) string {
+	if server.HasExtension("cloud_init") {
+		return VendorDataKey
+	}
+
+	return LegacyVendorDataKey
+}
+
+// HasVendorData returns true if vendor data is set in the given config.
+func HasVendorData(config map[string]string) bool {
+	return config[VendorDataKey] != "" || config[LegacyVendorDataKey] != ""
+}
+
+// AccessSudoRule is the sudoers rule granted to the access user.
+const AccessSudoRule = "ALL=(ALL) NOPASSWD:ALL"
+
+// AccessHomeDir returns the home directory of the access user.
+func AccessHomeDir(user string) string {
+	if user == "root" {
+		return "/root"
+	}
+
+	return path.Join("/home", user)
+}
+
+// AccessAuthorizedKeysPath returns the path of the authorized_keys file
+// of the access user.
+func AccessAuthorizedKeysPath(user string) string {
+	return path.Join(AccessHomeDir(user), ".ssh", "authorized_keys")
+}
+
+// AccessSudoersPath returns the path of the sudoers file granting sudo
+// to the access user.
+func AccessSudoersPath(user string) string {
+	return path.Join("/etc/sudoers.d", "90-lxd-"+user)
+}
+
+// AccessCloudConfig returns the cloud-config that creates the access user
+// with the given SSH authorized keys. JSON is a subset of YAML, therefore
+// the config is rendered as JSON.
+func AccessCloudConfig(user string, keys []string, sudo bool) (string, error) {
+	userConfig := map[string]any{
+		"name":                user,
+		"homedir":             AccessHomeDir(user),
+		"shell":               "/bin/bash",
+		"lock_passwd":         true,
+		"ssh_authorized_keys": keys,
+	}
+
+	if sudo {
+		userConfig["sudo"] = AccessSudoRule
+	}
+
+	// Keep the image's default user.
+	users := []any{"default", userConfig}
+	if user == "root" {
+		users = []any{"default"}
+	}
+
+	config := map[string]any{
+		"users": users,
+	}
+
+	if user == "root" {
+		config["disable_root"] = false
+		config["ssh_authorized_keys"] = keys
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("Failed to render cloud-config: %w", err)
+	}
+
+	return "#cloud-config\n" + string(data) + "\n", nil
+}
+
+// MergeAuthorizedKeys returns the given content of the authorized_keys file
+// with the missing keys appended and the revoked keys removed. Keys that
+// are both revoked and configured are kept. Other lines, including
+// comments, are left intact.
+func MergeAuthorizedKeys(content string, keys []string, revoked []string) string {
+	trimmed := func(keys []string) []string {
+		result := make([]string, 0, len(keys))
+		for _, key := range keys {
+			result = append(result, strings.TrimSpace(key))
+		}
+
+		return result
+	}
+
+	keys = trimmed(keys)
+	revoked = trimmed(revoked)
+
+	var b strings.Builder
+	var present []string
+	for line := range strings.Lines(content) {
+		key := strings.TrimSpace(line)
+		if slices.Contains(revoked, key) && !slices.Contains(keys, key) {
+			continue
+		}
+
+		present = append(present, key)
+		b.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	for _, key := range keys {
+		if !slices.Contains(present, key) {
+			b.WriteString(key + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// ParseAuthorizedKeys returns the keys of the given authorized_keys file
+// content. Empty lines and comments are skipped.
+func ParseAuthorizedKeys(content string) []string {
+	keys := []string{}
+	for line := range strings.Lines(content) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		keys = append(keys, line)
+	}
+
+	return keys
+}
+
+// PresentAuthorizedKeys returns the configured keys that are present in
+// the given authorized_keys, preserving the configured order. Keys that
+// are not configured are ignored.
+func PresentAuthorizedKeys(configured []string, authorized []string) []string {
+	present := make([]string, 0, len(configured))
+	for _, key := range configured {
+		if slices.Contains(authorized, strings.TrimSpace(key)) {
+			present = append(present, key)
+		}
+	}
+
+	return present
+}
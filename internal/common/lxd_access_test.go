@@ -0,0 +1,87 @@
+package common
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessPaths(t *testing.T) {
+	assert.Equal(t, "/root/.ssh/authorized_keys", AccessAuthorizedKeysPath("root"))
+	assert.Equal(t, "/home/ops/.ssh/authorized_keys", AccessAuthorizedKeysPath("ops"))
+	assert.Equal(t, "/etc/sudoers.d/90-lxd-ops", AccessSudoersPath("ops"))
+}
+
+func TestAccessCloudConfig(t *testing.T) {
+	keys := []string{"ssh-ed25519 AAAA1 alice", "ssh-ed25519 AAAA2 bob"}
+
+	config, err := AccessCloudConfig("ops", keys, true)
+	assert.NoError(t, err)
+
+	data, ok := strings.CutPrefix(config, "#cloud-config\n")
+	assert.True(t, ok)
+
+	var parsed struct {
+		Users []json.RawMessage `json:"users"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(data), &parsed))
+	assert.Len(t, parsed.Users, 2)
+	assert.JSONEq(t, `"default"`, string(parsed.Users[0]))
+	assert.JSONEq(t, `{
+		"name": "ops",
+		"homedir": "/home/ops",
+		"shell": "/bin/bash",
+		"lock_passwd": true,
+		"sudo": "ALL=(ALL) NOPASSWD:ALL",
+		"ssh_authorized_keys": ["ssh-ed25519 AAAA1 alice", "ssh-ed25519 AAAA2 bob"]
+	}`, string(parsed.Users[1]))
+
+	// Sudo is not granted unless requested.
+	config, err = AccessCloudConfig("ops", keys, false)
+	assert.NoError(t, err)
+	assert.NotContains(t, config, "sudo")
+}
+
+func TestAccessVendorDataKey(t *testing.T) {
+	assert.Equal(t, "cloud-init.vendor-data", AccessVendorDataKey(testExtensionServer{"cloud_init"}))
+	assert.Equal(t, "user.vendor-data", AccessVendorDataKey(testExtensionServer{}))
+}
+
+func TestHasVendorData(t *testing.T) {
+	assert.True(t, HasVendorData(map[string]string{"cloud-init.vendor-data": "#cloud-config"}))
+	assert.True(t, HasVendorData(map[string]string{"user.vendor-data": "#cloud-config"}))
+	assert.False(t, HasVendorData(map[string]string{"cloud-init.user-data": "#cloud-config", "user.vendor-data": ""}))
+}
+
+func TestMergeAuthorizedKeys(t *testing.T) {
+	content := "# Managed keys\nssh-ed25519 AAAA1 alice\nssh-ed25519 AAAA3 carol"
+	keys := []string{"ssh-ed25519 AAAA1 alice", "ssh-ed25519 AAAA2 bob"}
+
+	// Missing keys are appended, while other lines are left intact.
+	assert.Equal(t, "# Managed keys\nssh-ed25519 AAAA1 alice\nssh-ed25519 AAAA3 carol\nssh-ed25519 AAAA2 bob\n", MergeAuthorizedKeys(content, keys, nil))
+
+	// Revoked keys are removed unless they are configured.
+	revoked := []string{"ssh-ed25519 AAAA1 alice", "ssh-ed25519 AAAA3 carol"}
+	assert.Equal(t, "# Managed keys\nssh-ed25519 AAAA1 alice\nssh-ed25519 AAAA2 bob\n", MergeAuthorizedKeys(content, keys, revoked))
+	assert.Equal(t, "# Managed keys\n", MergeAuthorizedKeys(content, nil, revoked))
+
+	assert.Equal(t, "ssh-ed25519 AAAA1 alice\nssh-ed25519 AAAA2 bob\n", MergeAuthorizedKeys("", keys, nil))
+}
+
+func TestParseAuthorizedKeys(t *testing.T) {
+	content := "# Managed keys\nssh-ed25519 AAAA1 alice\n\n  ssh-ed25519 AAAA3 carol  \n"
+
+	assert.Equal(t, []string{"ssh-ed25519 AAAA1 alice", "ssh-ed25519 AAAA3 carol"}, ParseAuthorizedKeys(content))
+	assert.Equal(t, []string{}, ParseAuthorizedKeys(""))
+}
+
+func TestPresentAuthorizedKeys(t *testing.T) {
+	configured := []string{"ssh-ed25519 AAAA1 alice", "ssh-ed25519 AAAA2 bob"}
+	authorized := []string{"ssh-ed25519 AAAA3 carol", "ssh-ed25519 AAAA1 alice"}
+
+	assert.Equal(t, []string{"ssh-ed25519 AAAA1 alice"}, PresentAuthorizedKeys(configured, authorized))
+	assert.Equal(t, configured, PresentAuthorizedKeys(configured, append(authorized, "ssh-ed25519 AAAA2 bob")))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -40,6 +41,17 @@ func ToConfigMap(ctx context.Context, configMap types.Map) (map[string]string, d
 
 // ToConfigMapType converts map[string]string into config of type types.Map.
 func ToConfigMapType(ctx context.Context, config map[string]*string, modelConfig types.Map) (types.Map, diag.Diagnostics) {
+	return toConfigMapType(ctx, config, modelConfig, types.StringType)
+}
+
+// ToConfigValueMapType converts map[string]string into config of type
+// types.Map with elements of type ConfigValueType. It should be used for
+// config attributes whose values are compared semantically.
+func ToConfigValueMapType(ctx context.Context, config map[string]*string, modelConfig types.Map) (types.Map, diag.Diagnostics) {
+	return toConfigMapType(ctx, config, modelConfig, ConfigValueType{})
+}
+
+func toConfigMapType(ctx context.Context, config map[string]*string, modelConfig types.Map, elemType attr.Type) (types.Map, diag.Diagnostics) {
 	// Add any missing nil values.
 	nullConfig := map[string]*string{}
 	if !modelConfig.IsNull() && !modelConfig.IsUnknown() {
@@ -57,7 +69,7 @@ func ToConfigMapType(ctx context.Context, config map[string]*string, modelConfig
 		}
 	}
 
-	return types.MapValueFrom(ctx, types.StringType, config)
+	return types.MapValueFrom(ctx, elemType, config)
 }
 
 // ToNullableConfig converts map[string]string to map[string]*string.
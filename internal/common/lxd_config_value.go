@@ -0,0 +1,178 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/units"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = ConfigValueType{}
+	_ basetypes.StringValuableWithSemanticEquals = ConfigValue{}
+)
+
+// ConfigValueType is a string type used for elements of LXD configuration
+// maps. Its values are compared semantically, so that values normalized by
+// LXD (e.g. "1GB" and "1000MB") do not result in inconsistent state.
+type ConfigValueType struct {
+	basetypes.StringType
+}
+
+// Equal returns true if the given type is equivalent.
+func (t ConfigValueType) Equal(o attr.Type) bool {
+	_, ok := o.(ConfigValueType)
+	return ok
+}
+
+// String returns a human readable string of the type name.
+func (t ConfigValueType) String() string {
+	return "common.ConfigValueType"
+}
+
+// ValueFromString returns a StringValuable type given a StringValue.
+func (t ConfigValueType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return ConfigValue{StringValue: in}, nil
+}
+
+// ValueFromTerraform returns a Value given a tftypes.Value.
+func (t ConfigValueType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("Unexpected value type %T", attrValue)
+	}
+
+	return ConfigValue{StringValue: stringValue}, nil
+}
+
+// ValueType returns the Value type.
+func (t ConfigValueType) ValueType(_ context.Context) attr.Value {
+	return ConfigValue{}
+}
+
+// ConfigValue is a value of the ConfigValueType.
+type ConfigValue struct {
+	basetypes.StringValue
+}
+
+// Equal returns true if the given value is equivalent.
+func (v ConfigValue) Equal(o attr.Value) bool {
+	other, ok := o.(ConfigValue)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// Type returns the type of the value.
+func (v ConfigValue) Type(_ context.Context) attr.Type {
+	return ConfigValueType{}
+}
+
+// StringSemanticEquals returns true if the given config value is
+// semantically equal to the current one.
+func (v ConfigValue) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(ConfigValue)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T, but got %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+
+		return false, diags
+	}
+
+	return ConfigValuesEqual(v.ValueString(), newValue.ValueString()), diags
+}
+
+// Shapes of LXD configuration values.
+const (
+	configValueNumber   = "number"
+	configValueBool     = "bool"
+	configValueDuration = "duration"
+	configValueSize     = "size"
+)
+
+// configValueShape returns the shape of the given configuration value, or
+// an empty string if the value has no known shape. Plain numbers have a
+// separate shape, as they may represent a boolean, a size, or a duration.
+func configValueShape(value string) string {
+	_, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return configValueNumber
+	}
+
+	if shared.IsTrue(value) || shared.IsFalse(value) {
+		return configValueBool
+	}
+
+	_, err = time.ParseDuration(value)
+	if err == nil {
+		return configValueDuration
+	}
+
+	_, err = units.ParseByteSizeString(value)
+	if err == nil {
+		return configValueSize
+	}
+
+	return ""
+}
+
+// ConfigValuesEqual determines whether the two LXD configuration values are
+// semantically equal. The kind of the values is determined by their shape.
+// Values are considered equal if they represent the same boolean (e.g.
+// "true" and "on"), the same size (e.g. "1GB" and "1000MB"), or the same
+// duration (e.g. "1m" and "60s"). A plain number is compared as the kind
+// of the other value (e.g. "1" and "true", or "1024" and "1KiB"), while
+// values of different kinds are never equal.
+func ConfigValuesEqual(a string, b string) bool {
+	if a == b {
+		return true
+	}
+
+	// Empty values are considered unset in LXD.
+	if a == "" || b == "" {
+		return false
+	}
+
+	shape := configValueShape(a)
+	shapeB := configValueShape(b)
+	if shape == configValueNumber {
+		shape = shapeB
+	} else if shapeB != configValueNumber && shapeB != shape {
+		return false
+	}
+
+	switch shape {
+	case configValueBool:
+		return shared.IsTrue(a) && shared.IsTrue(b) || shared.IsFalse(a) && shared.IsFalse(b)
+
+	case configValueDuration:
+		aDuration, errA := time.ParseDuration(a)
+		bDuration, errB := time.ParseDuration(b)
+		return errA == nil && errB == nil && aDuration == bDuration
+
+	case configValueSize:
+		aSize, errA := units.ParseByteSizeString(a)
+		bSize, errB := units.ParseByteSizeString(b)
+		return errA == nil && errB == nil && aSize == bSize
+	}
+
+	return false
+}
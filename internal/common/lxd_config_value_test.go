@@ -0,0 +1,70 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValuesEqual(t *testing.T) {
+	tests := []struct {
+		Name  string
+		A     string
+		B     string
+		Equal bool
+	}{
+		{Name: "Identical", A: "abc", B: "abc", Equal: true},
+		{Name: "Different", A: "abc", B: "abd", Equal: false},
+		{Name: "Empty", A: "", B: "0", Equal: false},
+		{Name: "Bool true", A: "true", B: "1", Equal: true},
+		{Name: "Bool on", A: "on", B: "yes", Equal: true},
+		{Name: "Bool false", A: "false", B: "off", Equal: true},
+		{Name: "Bool mismatch", A: "true", B: "false", Equal: false},
+		{Name: "Size decimal", A: "1GB", B: "1000MB", Equal: true},
+		{Name: "Size binary", A: "1GiB", B: "1024MiB", Equal: true},
+		{Name: "Size bytes", A: "1KiB", B: "1024", Equal: true},
+		{Name: "Size mismatch", A: "1GB", B: "1GiB", Equal: false},
+		{Name: "Duration", A: "1m", B: "60s", Equal: true},
+		{Name: "Duration mismatch", A: "1m", B: "61s", Equal: false},
+		{Name: "Duration seconds", A: "0s", B: "0", Equal: true},
+		{Name: "Numbers", A: "1", B: "01", Equal: false},
+		{Name: "Bool and size", A: "1B", B: "true", Equal: false},
+		{Name: "Duration and size", A: "1s", B: "1B", Equal: false},
+		{Name: "Percentage", A: "50%", B: "0.5", Equal: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Equal, ConfigValuesEqual(test.A, test.B))
+			assert.Equal(t, test.Equal, ConfigValuesEqual(test.B, test.A))
+		})
+	}
+}
+
+func TestConfigValue_StringSemanticEquals(t *testing.T) {
+	ctx := context.Background()
+	prior := ConfigValue{StringValue: types.StringValue("1GB")}
+
+	equal, diags := prior.StringSemanticEquals(ctx, ConfigValue{StringValue: types.StringValue("1000MB")})
+	assert.False(t, diags.HasError())
+	assert.True(t, equal)
+
+	equal, diags = prior.StringSemanticEquals(ctx, ConfigValue{StringValue: types.StringValue("2GB")})
+	assert.False(t, diags.HasError())
+	assert.False(t, equal)
+
+	_, diags = prior.StringSemanticEquals(ctx, types.StringValue("1GB"))
+	assert.True(t, diags.HasError())
+}
+
+func TestToConfigValueMapType(t *testing.T) {
+	ctx := context.Background()
+	value := "1GB"
+
+	config, diags := ToConfigValueMapType(ctx, map[string]*string{"limits.memory": &value}, types.MapNull(ConfigValueType{}))
+	assert.False(t, diags.HasError())
+	assert.Equal(t, ConfigValueType{}, config.ElementType(ctx))
+	assert.Equal(t, ConfigValue{StringValue: types.StringValue("1GB")}, config.Elements()["limits.memory"])
+}
@@ -2,16 +2,46 @@ package common
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
 
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
 )
 
 // DeviceManagedByTerraform is used as a value for "user.managed-by" config key to signify that
 // resource is managed by the LXD provider.
 const DeviceManagedByTerraform = "terraform-lxd-provider"
 
+// Policies for devices that are not managed by Terraform, such as devices
+// added manually using "lxc config device add".
+const (
+	// UnmanagedDevicesIgnore excludes unmanaged devices from the state
+	// and preserves them when the resource is updated.
+	UnmanagedDevicesIgnore = "ignore"
+
+	// UnmanagedDevicesWarn behaves as UnmanagedDevicesIgnore, but also
+	// reports unmanaged devices on every plan.
+	UnmanagedDevicesWarn = "warn"
+
+	// UnmanagedDevicesRemove brings unmanaged devices into the state, so
+	// that the plan removes those not declared in the configuration.
+	UnmanagedDevicesRemove = "remove"
+
+	// UnmanagedDevicesAdopt marks unmanaged devices as managed on apply
+	// instead of removing them. Unmanaged devices that are declared in
+	// the configuration are brought into the state.
+	UnmanagedDevicesAdopt = "adopt"
+)
+
 type DeviceModel struct {
 	Name       types.String `tfsdk:"name"`
 	Type       types.String `tfsdk:"type"`
@@ -128,3 +158,283 @@ func ToDeviceMapType(ctx context.Context, devices map[string]map[string]string)
 
 	return types.MapValueFrom(ctx, types.ObjectType{AttrTypes: deviceType}, deviceMap)
 }
+
+// ServerDeviceKeys returns definitions of the properties of the given device
+// from the server metadata. NIC and GPU devices are matched by their
+// "nictype" and "gputype" properties. If the NIC type is not known, for
+// example when the NIC references a managed network, properties of all NIC
+// types are returned. Nil is returned if the server does not provide the
+// metadata configuration or the device type is not found.
+func ServerDeviceKeys(server lxd.InstanceServer, devType string, props map[string]string) (map[string]api.MetadataConfigKey, error) {
+	meta, err := serverMetadata(server)
+	if err != nil || meta == nil {
+		return nil, err
+	}
+
+	var entities []string
+	switch devType {
+	case "nic":
+		if props["nictype"] != "" {
+			entities = append(entities, "device-nic-"+props["nictype"])
+		} else {
+			for entity := range meta.Configs {
+				if strings.HasPrefix(entity, "device-nic-") {
+					entities = append(entities, entity)
+				}
+			}
+		}
+
+	case "gpu":
+		gpuType := props["gputype"]
+		if gpuType == "" {
+			gpuType = "physical"
+		}
+
+		entities = append(entities, "device-gpu-"+gpuType)
+	case "usb":
+		entities = append(entities, "device-unix-usb")
+	default:
+		entities = append(entities, "device-"+devType)
+	}
+
+	keys := make(map[string]api.MetadataConfigKey)
+	for _, entity := range entities {
+		maps.Copy(keys, metadataConfigKeys(meta, entity, "device-conf"))
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	return keys, nil
+}
+
+// MissingDeviceProperties returns quoted names of properties that are
+// required by the device type but are not set. Instance type may be empty if not known,
+// in which case properties required only by a specific instance type are
+// not reported.
+func MissingDeviceProperties(devType string, props map[string]string, instanceType string) []string {
+	var missing []string
+
+	requireAll := func(keys ...string) {
+		for _, k := range keys {
+			_, ok := props[k]
+			if !ok {
+				missing = append(missing, strconv.Quote(k))
+			}
+		}
+	}
+
+	requireAny := func(keys ...string) {
+		for _, k := range keys {
+			_, ok := props[k]
+			if ok {
+				return
+			}
+		}
+
+		quoted := make([]string, 0, len(keys))
+		for _, k := range keys {
+			quoted = append(quoted, strconv.Quote(k))
+		}
+
+		missing = append(missing, strings.Join(quoted, " or "))
+	}
+
+	switch devType {
+	case "disk":
+		// Root disk is identified by path "/" and requires a pool.
+		if props["path"] == "/" {
+			requireAll("pool")
+			break
+		}
+
+		requireAll("source")
+
+		// Disks of virtual machines can be attached without a path.
+		if instanceType == "container" {
+			requireAll("path")
+		}
+
+	case "nic":
+		requireAny("nictype", "network")
+	case "infiniband":
+		requireAll("nictype", "parent")
+	case "proxy":
+		requireAll("listen", "connect")
+	case "unix-char", "unix-block":
+		requireAny("source", "path")
+	case "pci":
+		requireAll("address")
+	case "tpm":
+		if instanceType == "container" {
+			requireAll("path")
+		}
+	}
+
+	return missing
+}
+
+// ValidateDevice validates properties of the device against the device
+// definitions from the server metadata, and ensures properties required by
+// the device type are set. Depending on the mode, issues are reported
+// either as errors or warnings.
+func ValidateDevice(server lxd.InstanceServer, devName string, devType string, properties types.Map, propertiesPath path.Path, instanceType string, mode string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if mode == ConfigValidationNone || properties.IsUnknown() {
+		return diags
+	}
+
+	// Unknown property values are still present in the map, which is
+	// sufficient to check for missing properties.
+	props := make(map[string]string, len(properties.Elements()))
+	for k, v := range properties.Elements() {
+		value, ok := v.(types.String)
+		if ok {
+			props[k] = value.ValueString()
+		}
+	}
+
+	missing := MissingDeviceProperties(devType, props, instanceType)
+	for _, k := range missing {
+		summary := "Missing device property"
+		detail := fmt.Sprintf("Device %q of type %q requires property %s.", devName, devType, k)
+
+		if mode == ConfigValidationWarning {
+			diags.AddAttributeWarning(propertiesPath, summary, detail)
+		} else {
+			diags.AddAttributeError(propertiesPath, summary, detail)
+		}
+	}
+
+	keys, err := ServerDeviceKeys(server, devType, props)
+	if err != nil {
+		diags.AddWarning("Failed to retrieve server metadata configuration", fmt.Sprintf("Properties of device %q cannot be validated: %v", devName, err))
+		return diags
+	}
+
+	diags.Append(validateKeys(properties, keys, propertiesPath, mode, "device property")...)
+	return diags
+}
+
+// ValidateDevices validates all devices in the given set of device blocks.
+// See ValidateDevice for details.
+func ValidateDevices(ctx context.Context, server lxd.InstanceServer, devices types.Set, devicesPath path.Path, instanceType string, mode string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if mode == ConfigValidationNone || devices.IsNull() || devices.IsUnknown() {
+		return diags
+	}
+
+	for _, v := range devices.Elements() {
+		obj, ok := v.(types.Object)
+		if !ok || obj.IsUnknown() {
+			continue
+		}
+
+		var device DeviceModel
+		diags.Append(obj.As(ctx, &device, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return diags
+		}
+
+		if device.Type.IsUnknown() {
+			continue
+		}
+
+		propertiesPath := devicesPath.AtSetValue(v).AtName("properties")
+		diags.Append(ValidateDevice(server, device.Name.ValueString(), device.Type.ValueString(), device.Properties, propertiesPath, instanceType, mode)...)
+	}
+
+	return diags
+}
+
+// IsManagedDevice returns true if the device is marked as managed by
+// Terraform.
+func IsManagedDevice(device map[string]string) bool {
+	return device[UserManagedBy] == DeviceManagedByTerraform
+}
+
+// TrackUnmanagedDevices returns true if the given policy brings all devices
+// not managed by Terraform into the state, so that their removal is planned.
+// Empty policy is treated as UnmanagedDevicesRemove.
+func TrackUnmanagedDevices(policy string) bool {
+	return policy != UnmanagedDevicesIgnore && policy != UnmanagedDevicesWarn && policy != UnmanagedDevicesAdopt
+}
+
+// SyncDevices returns devices that should be stored in the state. These
+// are the configured devices that exist on the server, and, depending on
+// the policy, devices not managed by Terraform. When unmanaged devices
+// are ignored, configured devices are kept only if they are managed,
+// so that the plan marks them as managed. When unmanaged devices are
+// adopted, configured devices are kept regardless, as they are marked
+// as managed on the next apply.
+func SyncDevices(devices map[string]map[string]string, configured map[string]map[string]string, policy string) map[string]map[string]string {
+	track := TrackUnmanagedDevices(policy)
+	result := make(map[string]map[string]string)
+
+	for name, device := range devices {
+		if !IsManagedDevice(device) {
+			// Add unmanaged devices, so that terraform plans
+			// their removal.
+			if track {
+				result[name] = device
+				continue
+			}
+
+			// Add configured devices that are being adopted.
+			_, ok := configured[name]
+			if ok && policy == UnmanagedDevicesAdopt {
+				result[name] = device
+			}
+
+			continue
+		}
+
+		// Skip managed devices that are not configured, as they are
+		// managed by other resources (e.g. instance device).
+		_, ok := configured[name]
+		if !ok {
+			continue
+		}
+
+		// Delete "user.managed-by" key from the state to avoid config mismatch.
+		device = maps.Clone(device)
+		delete(device, UserManagedBy)
+		result[name] = device
+	}
+
+	return result
+}
+
+// UnmanagedDeviceNames returns the sorted names of devices that are not
+// managed by Terraform and are not declared in the configuration.
+func UnmanagedDeviceNames(devices map[string]map[string]string, declared map[string]map[string]string) []string {
+	names := []string{}
+	for _, name := range utils.SortMapKeys(devices) {
+		_, ok := declared[name]
+		if !ok && !IsManagedDevice(devices[name]) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// UnmanagedDevicesWarnings returns a warning for each device of the given
+// resource (e.g. instance "c1") that is not managed by Terraform and is
+// not declared in the configuration.
+func UnmanagedDevicesWarnings(resource string, devices map[string]map[string]string, declared map[string]map[string]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, name := range UnmanagedDeviceNames(devices, declared) {
+		diags.AddAttributeWarning(
+			path.Root("unmanaged_devices"),
+			"Unmanaged device",
+			fmt.Sprintf(`Device %q of %s is not managed by Terraform. Declare the device in the configuration, or set "unmanaged_devices" to "remove" to remove it.`, name, resource),
+		)
+	}
+
+	return diags
+}
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingDeviceProperties(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Type         string
+		Properties   map[string]string
+		InstanceType string
+		Missing      []string
+	}{
+		{
+			Name:       "Root disk",
+			Type:       "disk",
+			Properties: map[string]string{"path": "/", "pool": "default"},
+		},
+		{
+			Name:       "Root disk without pool",
+			Type:       "disk",
+			Properties: map[string]string{"path": "/"},
+			Missing:    []string{`"pool"`},
+		},
+		{
+			Name:         "Container disk without path",
+			Type:         "disk",
+			Properties:   map[string]string{"source": "/tmp"},
+			InstanceType: "container",
+			Missing:      []string{`"path"`},
+		},
+		{
+			Name:         "Virtual machine disk without path",
+			Type:         "disk",
+			Properties:   map[string]string{"source": "vol", "pool": "default"},
+			InstanceType: "virtual-machine",
+		},
+		{
+			Name:       "Disk without source",
+			Type:       "disk",
+			Properties: map[string]string{"path": "/mnt"},
+			Missing:    []string{`"source"`},
+		},
+		{
+			Name:       "NIC with network",
+			Type:       "nic",
+			Properties: map[string]string{"network": "lxdbr0"},
+		},
+		{
+			Name:       "NIC without network or nictype",
+			Type:       "nic",
+			Properties: map[string]string{"name": "eth0"},
+			Missing:    []string{`"nictype" or "network"`},
+		},
+		{
+			Name:       "Proxy without connect",
+			Type:       "proxy",
+			Properties: map[string]string{"listen": "tcp:0.0.0.0:80"},
+			Missing:    []string{`"connect"`},
+		},
+		{
+			Name:       "GPU without properties",
+			Type:       "gpu",
+			Properties: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			missing := MissingDeviceProperties(test.Type, test.Properties, test.InstanceType)
+			assert.Equal(t, test.Missing, missing)
+		})
+	}
+}
+
+func TestSyncDevices(t *testing.T) {
+	devices := map[string]map[string]string{
+		"managed":    {"type": "disk", UserManagedBy: DeviceManagedByTerraform},
+		"other":      {"type": "disk", UserManagedBy: DeviceManagedByTerraform},
+		"manual":     {"type": "nic"},
+		"configured": {"type": "nic"},
+	}
+
+	configured := map[string]map[string]string{
+		"managed":    {"type": "disk"},
+		"configured": {"type": "nic"},
+	}
+
+	// Unmanaged devices are tracked by default.
+	assert.Equal(t, map[string]map[string]string{
+		"managed":    {"type": "disk"},
+		"manual":     {"type": "nic"},
+		"configured": {"type": "nic"},
+	}, SyncDevices(devices, configured, ""))
+
+	// Unmanaged devices are excluded even if configured, so that they
+	// are marked as managed on the next apply.
+	assert.Equal(t, map[string]map[string]string{
+		"managed": {"type": "disk"},
+	}, SyncDevices(devices, configured, UnmanagedDevicesIgnore))
+
+	// Configured unmanaged devices are kept when adopted, while
+	// others are excluded.
+	assert.Equal(t, map[string]map[string]string{
+		"managed":    {"type": "disk"},
+		"configured": {"type": "nic"},
+	}, SyncDevices(devices, configured, UnmanagedDevicesAdopt))
+
+	// Original devices are not modified.
+	assert.Equal(t, DeviceManagedByTerraform, devices["managed"][UserManagedBy])
+}
+
+func TestUnmanagedDeviceNames(t *testing.T) {
+	devices := map[string]map[string]string{
+		"managed":  {"type": "disk", UserManagedBy: DeviceManagedByTerraform},
+		"b":        {"type": "nic"},
+		"a":        {"type": "nic"},
+		"declared": {"type": "nic"},
+	}
+
+	declared := map[string]map[string]string{
+		"declared": {"type": "nic"},
+	}
+
+	assert.Equal(t, []string{"a", "b"}, UnmanagedDeviceNames(devices, declared))
+	assert.Len(t, UnmanagedDevicesWarnings(`instance "c1"`, devices, declared), 2)
+}
@@ -3,13 +3,18 @@ package common
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"time"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/gorilla/websocket"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/mitchellh/go-homedir"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
 )
 
@@ -17,30 +22,44 @@ import (
 type ExecTriggerType string
 
 const (
-	ON_CHANGE ExecTriggerType = "on_change"
-	ON_START  ExecTriggerType = "on_start"
-	ONCE      ExecTriggerType = "once"
+	ON_CHANGE  ExecTriggerType = "on_change"
+	ON_START   ExecTriggerType = "on_start"
+	ON_DESTROY ExecTriggerType = "on_destroy"
+	ONCE       ExecTriggerType = "once"
 )
 
+// execSignalKill is the signal number of SIGKILL, which is sent to the
+// command when it exceeds its timeout.
+const execSignalKill = 9
+
 func (t ExecTriggerType) String() string {
 	return string(t)
 }
 
 // ExecModel represents exec command to be executed on LXD instance.
 type ExecModel struct {
-	Command      types.List   `tfsdk:"command"`
-	Environment  types.Map    `tfsdk:"environment"`
-	WorkingDir   types.String `tfsdk:"working_dir"`
-	Trigger      types.String `tfsdk:"trigger"`
-	Enabled      types.Bool   `tfsdk:"enabled"`
-	RecordOutput types.Bool   `tfsdk:"record_output"`
-	FailOnError  types.Bool   `tfsdk:"fail_on_error"`
-	UserID       types.Int64  `tfsdk:"uid"`
-	GroupID      types.Int64  `tfsdk:"gid"`
-	ExitCode     types.Int64  `tfsdk:"exit_code"`
-	Output       types.String `tfsdk:"stdout"`
-	Error        types.String `tfsdk:"stderr"`
-	RunCount     types.Int64  `tfsdk:"run_count"`
+	Command         types.List   `tfsdk:"command"`
+	Environment     types.Map    `tfsdk:"environment"`
+	WorkingDir      types.String `tfsdk:"working_dir"`
+	Trigger         types.String `tfsdk:"trigger"`
+	Enabled         types.Bool   `tfsdk:"enabled"`
+	RecordOutput    types.Bool   `tfsdk:"record_output"`
+	FailOnError     types.Bool   `tfsdk:"fail_on_error"`
+	UserID          types.Int64  `tfsdk:"uid"`
+	GroupID         types.Int64  `tfsdk:"gid"`
+	Stdin           types.String `tfsdk:"stdin"`
+	StdinFile       types.String `tfsdk:"stdin_file"`
+	Timeout         types.String `tfsdk:"timeout"`
+	Retries         types.Int64  `tfsdk:"retries"`
+	RetryInterval   types.String `tfsdk:"retry_interval"`
+	MaxOutputBytes  types.Int64  `tfsdk:"max_output_bytes"`
+	SensitiveOutput types.Bool   `tfsdk:"sensitive_output"`
+	ExitCode        types.Int64  `tfsdk:"exit_code"`
+	Output          types.String `tfsdk:"stdout"`
+	Error           types.String `tfsdk:"stderr"`
+	SensitiveStdout types.String `tfsdk:"sensitive_stdout"`
+	SensitiveStderr types.String `tfsdk:"sensitive_stderr"`
+	RunCount        types.Int64  `tfsdk:"run_count"`
 }
 
 // IsTriggered determines whether the exec command needs to be executed.
@@ -60,13 +79,21 @@ func (e ExecModel) IsTriggered(isInstanceStarted bool) bool {
 	case ONCE:
 		return e.RunCount.ValueInt64() == 0
 	default:
-		// Unknown trigger type.
+		// Unknown trigger type or "on_destroy", which is
+		// handled separately by IsDestroyTriggered.
 		return false
 	}
 }
 
+// IsDestroyTriggered determines whether the exec command needs to be
+// executed before the instance is destroyed.
+func (e ExecModel) IsDestroyTriggered() bool {
+	return e.Enabled.ValueBool() && ExecTriggerType(e.Trigger.ValueString()) == ON_DESTROY
+}
+
 // Execute executes the exec command and populates the computed fields,
-// such as exit code, stdout, and stderr.
+// such as exit code, stdout, and stderr. If the command fails, it is
+// retried up to the configured number of retries.
 func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, instanceName string) diag.Diagnostics {
 	var diags diag.Diagnostics
 
@@ -79,6 +106,83 @@ func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, inst
 		return diags
 	}
 
+	timeout, err := parseExecDuration(e.Timeout, 0)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Invalid timeout for command %q", strings.Join(cmd, " ")), err.Error())
+		return diags
+	}
+
+	retryInterval, err := parseExecDuration(e.RetryInterval, 5*time.Second)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Invalid retry interval for command %q", strings.Join(cmd, " ")), err.Error())
+		return diags
+	}
+
+	var exitCode int64
+	var stdout string
+	var stderr string
+
+	for attempt := int64(0); attempt <= e.Retries.ValueInt64(); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(retryInterval):
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		exitCode, stdout, stderr, err = e.execute(ctx, server, instanceName, cmd, env, timeout)
+		if err == nil && exitCode == 0 {
+			break
+		}
+	}
+
+	// Fail on error (only if user requested).
+	if e.FailOnError.ValueBool() && (err != nil || exitCode != 0) {
+		detail := fmt.Sprintf("Command %q failed with an error (%d): %v", strings.Join(cmd, " "), exitCode, err)
+		if e.RecordOutput.ValueBool() && !e.SensitiveOutput.ValueBool() && stderr != "" {
+			detail += "\n\nStandard error:\n" + stderr
+		}
+
+		diags.AddError(fmt.Sprintf("Failed to execute command on instance %q", instanceName), detail)
+		return diags
+	}
+
+	if e.RecordOutput.ValueBool() && err != nil {
+		// If output is recorded and error is not nil, set
+		// error as stderr, because errBuf will be empty.
+		stderr = err.Error()
+	}
+
+	// Set command's computed values. Sensitive output is stored in
+	// separate attributes that are marked as sensitive.
+	e.RunCount = types.Int64Value(e.RunCount.ValueInt64() + 1)
+	e.ExitCode = types.Int64Value(exitCode)
+	e.Output = types.StringValue("")
+	e.Error = types.StringValue("")
+	e.SensitiveStdout = types.StringValue("")
+	e.SensitiveStderr = types.StringValue("")
+
+	if e.SensitiveOutput.ValueBool() {
+		e.SensitiveStdout = types.StringValue(stdout)
+		e.SensitiveStderr = types.StringValue(stderr)
+	} else {
+		e.Output = types.StringValue(stdout)
+		e.Error = types.StringValue(stderr)
+	}
+
+	return nil
+}
+
+// execute runs the command once and returns its exit code, stdout, and
+// stderr. Exit code -1 indicates the command was not executed or did not
+// complete. If timeout is positive and the command does not complete in
+// time, the command is killed.
+func (e ExecModel) execute(ctx context.Context, server lxd.InstanceServer, instanceName string, cmd []string, env map[string]string, timeout time.Duration) (int64, string, string, error) {
 	execReq := api.InstanceExecPost{
 		Command:      cmd,
 		Environment:  env,
@@ -95,17 +199,47 @@ func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, inst
 	var errBuf utils.Buffer
 
 	if e.RecordOutput.ValueBool() {
-		outBuf = utils.NewBufferCloser()
-		errBuf = utils.NewBufferCloser()
+		limit := e.MaxOutputBytes.ValueInt64()
+		outBuf = utils.NewLimitedBufferCloser(limit)
+		errBuf = utils.NewLimitedBufferCloser(limit)
 	} else {
 		outBuf = utils.NewDiscardCloser()
 		errBuf = utils.NewDiscardCloser()
 	}
 
+	stdin, err := e.stdinReader()
+	if err != nil {
+		return -1, "", "", err
+	}
+
+	if stdin != nil {
+		defer stdin.Close()
+	}
+
+	// Keep the control connection, so that the command can be
+	// killed if it is cancelled or exceeds its timeout. The connection
+	// is closed once the command has finished.
+	controlCh := make(chan *websocket.Conn, 1)
+	controlDone := make(chan struct{})
+	defer close(controlDone)
+
 	execArgs := lxd.InstanceExecArgs{
+		Stdin:    stdin,
 		Stdout:   outBuf,
 		Stderr:   errBuf,
 		DataDone: make(chan bool),
+		Control: func(conn *websocket.Conn) {
+			controlCh <- conn
+			<-controlDone
+			_ = conn.Close()
+		},
+	}
+
+	execCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
 	// Exit code -1 indicates the command was not executed.
@@ -114,16 +248,31 @@ func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, inst
 	// Run command.
 	opExec, err := server.ExecInstance(instanceName, execReq, &execArgs)
 	if err == nil {
-		err = opExec.WaitContext(ctx)
+		err = opExec.WaitContext(execCtx)
 		if err == nil {
 			// Wait for any remaining output to be flushed.
 			select {
-			case <-ctx.Done():
-				err = ctx.Err()
+			case <-execCtx.Done():
+				err = execCtx.Err()
 			case <-execArgs.DataDone:
 			}
 		}
 
+		if err != nil && execCtx.Err() != nil {
+			// Command was cancelled or exceeded its own timeout. Kill
+			// it to prevent leaving the process running within the
+			// instance.
+			select {
+			case conn := <-controlCh:
+				_ = conn.WriteJSON(api.InstanceExecControl{Command: "signal", Signal: execSignalKill})
+			default:
+			}
+
+			if ctx.Err() == nil {
+				err = fmt.Errorf("Command timed out after %s", timeout)
+			}
+		}
+
 		// Extract exit code from operation's metadata.
 		opMeta := opExec.Get().Metadata
 		if opMeta != nil {
@@ -134,28 +283,42 @@ func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, inst
 		}
 	}
 
-	// Fail on error (only if user requested).
-	if e.FailOnError.ValueBool() && (err != nil || exitCode != 0) {
-		diags.AddError(
-			fmt.Sprintf("Failed to execute command on instance %q", instanceName),
-			fmt.Sprintf("Command %q failed with an error (%d): %v", strings.Join(cmd, " "), exitCode, err),
-		)
-		return diags
+	return exitCode, outBuf.String(), errBuf.String(), err
+}
+
+// stdinReader returns a reader of the command's standard input. The input
+// is read either from the configured content or from a local file. If
+// neither is configured, nil is returned.
+func (e ExecModel) stdinReader() (io.ReadCloser, error) {
+	if e.Stdin.ValueString() != "" {
+		return io.NopCloser(strings.NewReader(e.Stdin.ValueString())), nil
 	}
 
-	// Set command's computed values.
-	e.RunCount = types.Int64Value(e.RunCount.ValueInt64() + 1)
-	e.ExitCode = types.Int64Value(exitCode)
-	e.Output = types.StringValue(outBuf.String())
-	e.Error = types.StringValue(errBuf.String())
+	if e.StdinFile.ValueString() != "" {
+		path, err := homedir.Expand(e.StdinFile.ValueString())
+		if err != nil {
+			return nil, fmt.Errorf("Unable to determine stdin file path: %v", err)
+		}
 
-	if e.RecordOutput.ValueBool() && err != nil {
-		// If output is recorded and error is not nil, set
-		// error as stderr, because errBuf will be empty.
-		e.Error = types.StringValue(err.Error())
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read stdin file: %v", err)
+		}
+
+		return f, nil
 	}
 
-	return nil
+	return nil, nil
+}
+
+// parseExecDuration parses the duration from the given value. If the value
+// is null or empty, the default duration is returned.
+func parseExecDuration(value types.String, def time.Duration) (time.Duration, error) {
+	if value.ValueString() == "" {
+		return def, nil
+	}
+
+	return time.ParseDuration(value.ValueString())
 }
 
 // ToExecMap converts execs schema into map of exec models.
@@ -172,6 +335,8 @@ func ToExecMap(ctx context.Context, execMap types.Map) (map[string]*ExecModel, d
 		e.ExitCode = types.Int64Value(-1)
 		e.Output = types.StringValue("")
 		e.Error = types.StringValue("")
+		e.SensitiveStdout = types.StringValue("")
+		e.SensitiveStderr = types.StringValue("")
 
 		if e.RunCount.IsUnknown() {
 			e.RunCount = types.Int64Value(0)
@@ -186,19 +351,28 @@ func ToExecMap(ctx context.Context, execMap types.Map) (map[string]*ExecModel, d
 // ToExecMapType converts map of exec models into schema type.
 func ToExecMapType(ctx context.Context, execs map[string]*ExecModel) (types.Map, diag.Diagnostics) {
 	execType := map[string]attr.Type{
-		"command":       types.ListType{ElemType: types.StringType},
-		"environment":   types.MapType{ElemType: types.StringType},
-		"working_dir":   types.StringType,
-		"trigger":       types.StringType,
-		"enabled":       types.BoolType,
-		"record_output": types.BoolType,
-		"fail_on_error": types.BoolType,
-		"uid":           types.Int64Type,
-		"gid":           types.Int64Type,
-		"exit_code":     types.Int64Type,
-		"stdout":        types.StringType,
-		"stderr":        types.StringType,
-		"run_count":     types.Int64Type,
+		"command":          types.ListType{ElemType: types.StringType},
+		"environment":      types.MapType{ElemType: types.StringType},
+		"working_dir":      types.StringType,
+		"trigger":          types.StringType,
+		"enabled":          types.BoolType,
+		"record_output":    types.BoolType,
+		"fail_on_error":    types.BoolType,
+		"uid":              types.Int64Type,
+		"gid":              types.Int64Type,
+		"stdin":            types.StringType,
+		"stdin_file":       types.StringType,
+		"timeout":          types.StringType,
+		"retries":          types.Int64Type,
+		"retry_interval":   types.StringType,
+		"max_output_bytes": types.Int64Type,
+		"sensitive_output": types.BoolType,
+		"exit_code":        types.Int64Type,
+		"stdout":           types.StringType,
+		"stderr":           types.StringType,
+		"sensitive_stdout": types.StringType,
+		"sensitive_stderr": types.StringType,
+		"run_count":        types.Int64Type,
 	}
 
 	return types.MapValueFrom(ctx, types.ObjectType{AttrTypes: execType}, execs)
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+// ExtensionServer is an LXD server that can be queried for API extensions.
+// It is satisfied by lxd.InstanceServer.
+type ExtensionServer interface {
+	HasExtension(extension string) bool
+}
+
+// CheckResourceExtensions returns an error diagnostic for each of the
+// given API extensions that is required by the resource, but is not
+// supported by the server.
+func CheckResourceExtensions(server ExtensionServer, resourceName string, extensions ...string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, ext := range extensions {
+		if server.HasExtension(ext) {
+			continue
+		}
+
+		diags.AddError(
+			"Missing LXD API extension",
+			missingExtensionDetail(fmt.Sprintf("Resource %q", resourceName), ext),
+		)
+	}
+
+	return diags
+}
+
+// CheckAttributeExtensions returns an attribute error diagnostic for each
+// of the given API extensions that is required by the attribute (or its
+// current value), but is not supported by the server.
+func CheckAttributeExtensions(server ExtensionServer, attrPath path.Path, extensions ...string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, ext := range extensions {
+		if server.HasExtension(ext) {
+			continue
+		}
+
+		diags.AddAttributeError(
+			attrPath,
+			"Missing LXD API extension",
+			missingExtensionDetail(fmt.Sprintf("Attribute %q", attrPath.String()), ext),
+		)
+	}
+
+	return diags
+}
+
+// missingExtensionDetail returns an actionable message for the API
+// extension missing on the server.
+func missingExtensionDetail(subject string, extension string) string {
+	detail := fmt.Sprintf("%s requires the LXD API extension %q, which is not supported by the server.", subject, extension)
+
+	version, ok := provider_config.SupportedLXDVersion(extension)
+	if ok {
+		return detail + fmt.Sprintf(" Please upgrade the LXD server to version %s or later.", version)
+	}
+
+	return detail + " Please upgrade the LXD server to a version that supports it."
+}
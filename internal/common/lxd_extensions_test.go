@@ -0,0 +1,38 @@
+package common
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/stretchr/testify/assert"
+)
+
+type testExtensionServer []string
+
+func (s testExtensionServer) HasExtension(extension string) bool {
+	return slices.Contains(s, extension)
+}
+
+func TestCheckResourceExtensions(t *testing.T) {
+	server := testExtensionServer{"storage_buckets"}
+
+	diags := CheckResourceExtensions(server, "lxd_storage_bucket", "storage_buckets")
+	assert.False(t, diags.HasError())
+
+	diags = CheckResourceExtensions(server, "lxd_auth_group", "access_management")
+	assert.Equal(t, 1, diags.ErrorsCount())
+	assert.Equal(t, `Resource "lxd_auth_group" requires the LXD API extension "access_management", which is not supported by the server. Please upgrade the LXD server to version 5.21.0 or later.`, diags.Errors()[0].Detail())
+}
+
+func TestCheckAttributeExtensions(t *testing.T) {
+	server := testExtensionServer{}
+
+	diags := CheckAttributeExtensions(server, path.Root("auth_method"), "auth_bearer")
+	assert.Equal(t, 1, diags.ErrorsCount())
+	assert.Equal(t, `Attribute "auth_method" requires the LXD API extension "auth_bearer", which is not supported by the server. Please upgrade the LXD server to version 6.5.0 or later.`, diags.Errors()[0].Detail())
+
+	diags = CheckAttributeExtensions(server, path.Root("config"), "unknown_extension")
+	assert.Equal(t, 1, diags.ErrorsCount())
+	assert.Equal(t, `Attribute "config" requires the LXD API extension "unknown_extension", which is not supported by the server. Please upgrade the LXD server to a version that supports it.`, diags.Errors()[0].Detail())
+}
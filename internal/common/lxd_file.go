@@ -1,20 +1,27 @@
 package common
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"text/template"
 
 	lxd "github.com/canonical/lxd/client"
 	lxdShared "github.com/canonical/lxd/shared"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/mitchellh/go-homedir"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
 )
 
 type InstanceFileModel struct {
@@ -26,6 +33,72 @@ type InstanceFileModel struct {
 	Mode       types.String `tfsdk:"mode"`
 	CreateDirs types.Bool   `tfsdk:"create_directories"`
 	Append     types.Bool   `tfsdk:"append"`
+
+	// Directory uploads.
+	SourceDir types.String `tfsdk:"source_dir"`
+	Exclude   types.List   `tfsdk:"exclude"`
+
+	// Templates.
+	Template types.Bool `tfsdk:"template"`
+
+	// Drift detection.
+	SkipContentCheck types.Bool   `tfsdk:"skip_content_check"`
+	SHA256           types.String `tfsdk:"sha256"`   // Computed.
+	Manifest         types.Map    `tfsdk:"manifest"` // Computed.
+}
+
+// IsDir returns true if the model represents a directory upload.
+func (f InstanceFileModel) IsDir() bool {
+	return f.SourceDir.ValueString() != ""
+}
+
+// IsTemplate returns true if the file content is a template that is
+// rendered with instance facts before upload.
+func (f InstanceFileModel) IsTemplate() bool {
+	return f.Template.ValueBool()
+}
+
+// IsContentChecked returns true if the content of the file within the
+// instance should be compared against the uploaded content. Appended
+// files are never checked, as their content is not fully managed.
+// Content of uploaded directories is tracked only by the checksum of
+// the local source directory manifest.
+func (f InstanceFileModel) IsContentChecked() bool {
+	return !f.SkipContentCheck.ValueBool() && !f.Append.ValueBool() && !f.IsDir()
+}
+
+// ExcludePatterns returns the list of glob patterns excluded from
+// directory uploads.
+func (f InstanceFileModel) ExcludePatterns() ([]string, error) {
+	if f.Exclude.IsNull() || f.Exclude.IsUnknown() {
+		return nil, nil
+	}
+
+	excludes := make([]string, 0, len(f.Exclude.Elements()))
+	for _, v := range f.Exclude.Elements() {
+		pattern, ok := v.(types.String)
+		if !ok || pattern.IsNull() || pattern.IsUnknown() {
+			continue
+		}
+
+		_, err := path.Match(pattern.ValueString(), "")
+		if err != nil {
+			return nil, fmt.Errorf("Invalid exclude pattern %q: %v", pattern.ValueString(), err)
+		}
+
+		excludes = append(excludes, pattern.ValueString())
+	}
+
+	return excludes, nil
+}
+
+// InstanceFileInfo represents the current state of a file within
+// an instance.
+type InstanceFileInfo struct {
+	UID    int64
+	GID    int64
+	Mode   int
+	SHA256 string
 }
 
 // ToFileMap converts files from types.Set into map[string]LxdFileModel.
@@ -51,12 +124,153 @@ func ToFileMap(ctx context.Context, fileSet types.Set) (map[string]InstanceFileM
 
 // ToFileSetType converts files from a map[string]LxdFileModel into types.Set.
 func ToFileSetType(ctx context.Context, fileMap map[string]InstanceFileModel) (types.Set, diag.Diagnostics) {
+	fileType := map[string]attr.Type{
+		"content":            types.StringType,
+		"source_path":        types.StringType,
+		"target_path":        types.StringType,
+		"uid":                types.Int64Type,
+		"gid":                types.Int64Type,
+		"mode":               types.StringType,
+		"create_directories": types.BoolType,
+		"append":             types.BoolType,
+		"source_dir":         types.StringType,
+		"exclude":            types.ListType{ElemType: types.StringType},
+		"template":           types.BoolType,
+		"skip_content_check": types.BoolType,
+		"sha256":             types.StringType,
+		"manifest":           types.MapType{ElemType: types.StringType},
+	}
+
 	files := make([]InstanceFileModel, 0, len(fileMap))
-	for _, v := range fileMap {
-		files = append(files, v)
+	for _, k := range utils.SortMapKeys(fileMap) {
+		files = append(files, fileMap[k])
 	}
 
-	return types.SetValueFrom(ctx, types.ObjectType{}, files)
+	return types.SetValueFrom(ctx, types.ObjectType{AttrTypes: fileType}, files)
+}
+
+// InstanceFileHash returns the SHA-256 checksum of the file's local
+// content, which is read either from content or from the source file.
+// For directory uploads, the checksum of the source directory manifest
+// is returned.
+func InstanceFileHash(file InstanceFileModel) (string, error) {
+	if file.IsDir() {
+		excludes, err := file.ExcludePatterns()
+		if err != nil {
+			return "", err
+		}
+
+		entries, err := scanSourceDir(file.SourceDir.ValueString(), excludes)
+		if err != nil {
+			return "", err
+		}
+
+		return sourceDirManifestHash(entries), nil
+	}
+
+	hash := sha256.New()
+
+	sourcePath := file.SourcePath.ValueString()
+	if sourcePath != "" {
+		path, err := homedir.Expand(sourcePath)
+		if err != nil {
+			return "", fmt.Errorf("Unable to determine source file path: %v", err)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("Unable to read source file: %v", err)
+		}
+		defer f.Close()
+
+		_, err = io.Copy(hash, f)
+		if err != nil {
+			return "", fmt.Errorf("Unable to read source file: %v", err)
+		}
+	} else {
+		_, _ = hash.Write([]byte(file.Content.ValueString()))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetInstanceFileInfo retrieves the owner and mode of a file within an
+// instance. If hashContent is true, the file content is hashed as well.
+func GetInstanceFileInfo(server lxd.InstanceServer, instanceName string, targetPath string, hashContent bool) (*InstanceFileInfo, error) {
+	targetPath, err := toAbsFilePath(targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, resp, err := server.GetInstanceFile(instanceName, targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if reader != nil {
+		defer reader.Close()
+	}
+
+	info := &InstanceFileInfo{
+		UID:  resp.UID,
+		GID:  resp.GID,
+		Mode: resp.Mode,
+	}
+
+	if hashContent && reader != nil {
+		hash := sha256.New()
+		_, err = io.Copy(hash, reader)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read file %q: %v", targetPath, err)
+		}
+
+		info.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	return info, nil
+}
+
+// RenderInstanceFile renders the file content (or the content of the source
+// file) as a Go text/template using the provided data. It returns a copy of
+// the file model with the rendered content.
+func RenderInstanceFile(file InstanceFileModel, data any) (InstanceFileModel, error) {
+	if file.IsDir() {
+		return file, fmt.Errorf("Directories uploaded using %q cannot be rendered as templates", "source_dir")
+	}
+
+	text := file.Content.ValueString()
+
+	sourcePath := file.SourcePath.ValueString()
+	if sourcePath != "" {
+		path, err := homedir.Expand(sourcePath)
+		if err != nil {
+			return file, fmt.Errorf("Unable to determine source file path: %v", err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return file, fmt.Errorf("Unable to read source file: %v", err)
+		}
+
+		text = string(content)
+	}
+
+	targetPath := file.TargetPath.ValueString()
+	tmpl, err := template.New(targetPath).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return file, fmt.Errorf("Failed to parse template for file %q: %v", targetPath, err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return file, fmt.Errorf("Failed to render template for file %q: %v", targetPath, err)
+	}
+
+	file.Content = types.StringValue(buf.String())
+	file.SourcePath = types.StringNull()
+
+	return file, nil
 }
 
 // InstanceFileDelete deletes a file from an instance.
@@ -74,7 +288,8 @@ func InstanceFileDelete(server lxd.InstanceServer, instanceName string, targetPa
 	return nil
 }
 
-// InstanceFileUpload uploads a file to an instance.
+// InstanceFileUpload uploads a file to an instance. If a source directory
+// is set, the directory is uploaded instead.
 func InstanceFileUpload(server lxd.InstanceServer, instanceName string, file InstanceFileModel) error {
 	content := file.Content.ValueString()
 	sourcePath := file.SourcePath.ValueString()
@@ -83,6 +298,14 @@ func InstanceFileUpload(server lxd.InstanceServer, instanceName string, file Ins
 		return fmt.Errorf("File %q and %q are mutually exclusive.", "content", "source_path")
 	}
 
+	if file.IsDir() {
+		if content != "" || sourcePath != "" {
+			return fmt.Errorf("File %q is mutually exclusive with %q and %q.", "source_dir", "content", "source_path")
+		}
+
+		return InstanceDirUpload(server, instanceName, file, nil)
+	}
+
 	targetPath, err := toAbsFilePath(file.TargetPath.ValueString())
 	if err != nil {
 		return err
@@ -0,0 +1,551 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/mitchellh/go-homedir"
+	"github.com/pkg/sftp"
+	"golang.org/x/sync/errgroup"
+)
+
+// sftpMaxParallelTransfers is the maximum number of files that are
+// transferred to the instance concurrently.
+const sftpMaxParallelTransfers = 8
+
+// dirEntry represents a single entry of a local source directory.
+type dirEntry struct {
+	relPath    string      // Slash separated path relative to the source directory.
+	localPath  string      // Local path of the entry.
+	mode       fs.FileMode // Permissions and type of the entry.
+	linkTarget string      // Symlink target (only for symlinks).
+	sha256     string      // Content checksum (only for regular files).
+}
+
+// isDir returns true if the entry is a directory.
+func (e dirEntry) isDir() bool {
+	return e.mode.IsDir()
+}
+
+// isSymlink returns true if the entry is a symbolic link.
+func (e dirEntry) isSymlink() bool {
+	return e.mode&fs.ModeSymlink != 0
+}
+
+// kind returns the type of the entry as a string.
+func (e dirEntry) kind() string {
+	switch {
+	case e.isDir():
+		return "directory"
+	case e.isSymlink():
+		return "symlink"
+	default:
+		return "file"
+	}
+}
+
+// isExcluded returns true if the slash separated relative path matches
+// any of the exclude patterns. Patterns are matched against the whole
+// relative path and against the base name of the entry.
+func isExcluded(relPath string, excludes []string) bool {
+	for _, pattern := range excludes {
+		ok, _ := path.Match(pattern, relPath)
+		if ok {
+			return true
+		}
+
+		ok, _ = path.Match(pattern, path.Base(relPath))
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scanSourceDir walks the local source directory and returns its entries
+// sorted by their relative path, so that parent directories always precede
+// their children. Symbolic links are not followed.
+func scanSourceDir(sourceDir string, excludes []string) ([]dirEntry, error) {
+	root, err := homedir.Expand(sourceDir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to determine source directory path: %v", err)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read source directory: %v", err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("Source path %q is not a directory", sourceDir)
+	}
+
+	var entries []dirEntry
+
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		if isExcluded(rel, excludes) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		entry := dirEntry{
+			relPath:   rel,
+			localPath: p,
+			mode:      info.Mode(),
+		}
+
+		switch {
+		case entry.isSymlink():
+			entry.linkTarget, err = os.Readlink(p)
+			if err != nil {
+				return err
+			}
+
+		case info.Mode().IsRegular():
+			entry.sha256, err = hashLocalFile(p)
+			if err != nil {
+				return err
+			}
+
+		case !info.IsDir():
+			// Skip special files, such as sockets and devices.
+			return nil
+		}
+
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read source directory %q: %v", sourceDir, err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].relPath < entries[j].relPath
+	})
+
+	return entries, nil
+}
+
+// hashLocalFile returns the SHA-256 checksum of a local file.
+func hashLocalFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// manifestValue returns the manifest value of the entry, which consists
+// of its type, permissions, and content checksum (or symlink target).
+func (e dirEntry) manifestValue() string {
+	content := e.sha256
+	if e.isSymlink() {
+		content = e.linkTarget
+	}
+
+	return fmt.Sprintf("%s:%04o:%s", e.kind(), e.mode.Perm(), content)
+}
+
+// sourceDirManifest returns the manifest of the source directory, which
+// maps the relative path of each entry to its manifest value.
+func sourceDirManifest(entries []dirEntry) map[string]string {
+	manifest := make(map[string]string, len(entries))
+	for _, e := range entries {
+		manifest[e.relPath] = e.manifestValue()
+	}
+
+	return manifest
+}
+
+// sourceDirManifestHash returns the SHA-256 checksum of the source
+// directory manifest. The manifest consists of the relative path, type,
+// permissions, and content checksum (or symlink target) of each entry,
+// therefore any change of the directory tree results in a new checksum.
+func sourceDirManifestHash(entries []dirEntry) string {
+	hash := sha256.New()
+	for _, e := range entries {
+		content := e.sha256
+		if e.isSymlink() {
+			content = e.linkTarget
+		}
+
+		_, _ = fmt.Fprintf(hash, "%s\x00%s\x00%04o\x00%s\n", e.relPath, e.kind(), e.mode.Perm(), content)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// InstanceDirManifest returns the manifest of the source directory of
+// the given directory upload. The manifest maps the path of each entry
+// relative to the source directory to its type, permissions, and content
+// checksum (or symlink target).
+func InstanceDirManifest(file InstanceFileModel) (types.Map, error) {
+	excludes, err := file.ExcludePatterns()
+	if err != nil {
+		return types.MapNull(types.StringType), err
+	}
+
+	entries, err := scanSourceDir(file.SourceDir.ValueString(), excludes)
+	if err != nil {
+		return types.MapNull(types.StringType), err
+	}
+
+	elems := make(map[string]attr.Value, len(entries))
+	for rel, value := range sourceDirManifest(entries) {
+		elems[rel] = types.StringValue(value)
+	}
+
+	return types.MapValueMust(types.StringType, elems), nil
+}
+
+// InstanceDirUpload synchronizes the local source directory with the
+// target directory within the instance using the instance SFTP endpoint.
+// If the previous upload of the directory is provided, only entries that
+// were added or changed since are uploaded, and entries of the previous
+// manifest that no longer exist in the source directory (and are not
+// excluded) are removed. Other entries within the target directory are
+// left intact. Files are transferred concurrently.
+func InstanceDirUpload(server lxd.InstanceServer, instanceName string, file InstanceFileModel, previous *InstanceFileModel) error {
+	targetPath := path.Clean(file.TargetPath.ValueString())
+	if !path.IsAbs(targetPath) {
+		return fmt.Errorf("Target directory %q must be an absolute path", targetPath)
+	}
+
+	if targetPath == "/" {
+		return fmt.Errorf("Refusing to upload directory into root directory of instance %q", instanceName)
+	}
+
+	// Entries of the previous manifest are considered uploaded, and
+	// their owner is set only if it has changed.
+	prevManifest := map[string]string{}
+	ownerChanged := true
+	if previous != nil && previous.IsDir() {
+		if !previous.Manifest.IsNull() && !previous.Manifest.IsUnknown() {
+			_ = previous.Manifest.ElementsAs(context.Background(), &prevManifest, false)
+		}
+
+		ownerChanged = !previous.UserID.Equal(file.UserID) || !previous.GroupID.Equal(file.GroupID)
+	}
+
+	excludes, err := file.ExcludePatterns()
+	if err != nil {
+		return err
+	}
+
+	entries, err := scanSourceDir(file.SourceDir.ValueString(), excludes)
+	if err != nil {
+		return err
+	}
+
+	client, err := server.GetInstanceFileSFTP(instanceName)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to instance SFTP endpoint: %v", err)
+	}
+	defer client.Close()
+
+	// Create the target directory.
+	if file.CreateDirs.ValueBool() {
+		err = client.MkdirAll(targetPath)
+	} else {
+		err = client.Mkdir(targetPath)
+		if err != nil {
+			info, statErr := client.Stat(targetPath)
+			if statErr == nil && info.IsDir() {
+				err = nil
+			}
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("Could not create directory %q: %v", targetPath, err)
+	}
+
+	err = chownInstancePath(client, targetPath, file)
+	if err != nil {
+		return err
+	}
+
+	// Remove previously uploaded entries that no longer exist locally.
+	err = pruneInstanceDir(client, targetPath, prevManifest, entries, excludes)
+	if err != nil {
+		return err
+	}
+
+	// Create directories and symlinks first, as files are
+	// transferred concurrently.
+	var files []dirEntry
+	for _, e := range entries {
+		remotePath := path.Join(targetPath, e.relPath)
+		unchanged := prevManifest[e.relPath] == e.manifestValue()
+
+		switch {
+		case unchanged && e.isSymlink():
+			continue
+
+		case unchanged:
+			// Entry is already uploaded, but its owner may
+			// need to be updated.
+			if !ownerChanged {
+				continue
+			}
+
+		case e.isDir():
+			err := client.Mkdir(remotePath)
+			if err != nil {
+				info, statErr := client.Lstat(remotePath)
+				if statErr != nil || !info.IsDir() {
+					return fmt.Errorf("Could not create directory %q: %v", remotePath, err)
+				}
+			}
+
+			err = client.Chmod(remotePath, e.mode.Perm())
+			if err != nil {
+				return fmt.Errorf("Could not set mode of directory %q: %v", remotePath, err)
+			}
+
+		case e.isSymlink():
+			err := client.Remove(remotePath)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("Could not replace symlink %q: %v", remotePath, err)
+			}
+
+			err = client.Symlink(e.linkTarget, remotePath)
+			if err != nil {
+				return fmt.Errorf("Could not create symlink %q: %v", remotePath, err)
+			}
+
+			// Symlink ownership cannot be changed over SFTP, as
+			// the chown would affect the link target.
+			continue
+
+		default:
+			files = append(files, e)
+			continue
+		}
+
+		err = chownInstancePath(client, remotePath, file)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Transfer files with bounded parallelism.
+	g := errgroup.Group{}
+	g.SetLimit(sftpMaxParallelTransfers)
+
+	for _, e := range files {
+		g.Go(func() error {
+			remotePath := path.Join(targetPath, e.relPath)
+
+			err := uploadInstanceFileSFTP(client, e.localPath, remotePath, e.mode.Perm())
+			if err != nil {
+				return err
+			}
+
+			return chownInstancePath(client, remotePath, file)
+		})
+	}
+
+	return g.Wait()
+}
+
+// InstanceDirDelete removes the target directory and its content from
+// the instance.
+func InstanceDirDelete(server lxd.InstanceServer, instanceName string, targetPath string) error {
+	targetPath = path.Clean(targetPath)
+	if targetPath == "/" {
+		return fmt.Errorf("Refusing to remove root directory of instance %q", instanceName)
+	}
+
+	client, err := server.GetInstanceFileSFTP(instanceName)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to instance SFTP endpoint: %v", err)
+	}
+	defer client.Close()
+
+	err = removeInstancePath(client, targetPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Could not remove directory %q: %v", targetPath, err)
+	}
+
+	return nil
+}
+
+// uploadInstanceFileSFTP uploads a single local file to the instance.
+func uploadInstanceFileSFTP(client *sftp.Client, localPath string, remotePath string, mode fs.FileMode) error {
+	src, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("Unable to read source file: %v", err)
+	}
+	defer src.Close()
+
+	dst, err := client.OpenFile(remotePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return fmt.Errorf("Could not upload file %q: %v", remotePath, err)
+	}
+
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("Could not upload file %q: %v", remotePath, err)
+	}
+
+	err = dst.Close()
+	if err != nil {
+		return fmt.Errorf("Could not upload file %q: %v", remotePath, err)
+	}
+
+	err = client.Chmod(remotePath, mode)
+	if err != nil {
+		return fmt.Errorf("Could not set mode of file %q: %v", remotePath, err)
+	}
+
+	return nil
+}
+
+// chownInstancePath sets the owner of the given path within the instance,
+// if the owner is configured.
+func chownInstancePath(client *sftp.Client, remotePath string, file InstanceFileModel) error {
+	if file.UserID.IsNull() && file.GroupID.IsNull() {
+		return nil
+	}
+
+	err := client.Chown(remotePath, int(file.UserID.ValueInt64()), int(file.GroupID.ValueInt64()))
+	if err != nil {
+		return fmt.Errorf("Could not set owner of %q: %v", remotePath, err)
+	}
+
+	return nil
+}
+
+// manifestKind returns the type of the entry from its manifest value.
+func manifestKind(value string) string {
+	kind, _, _ := strings.Cut(value, ":")
+	return kind
+}
+
+// prunedManifestPaths returns relative paths of the previous manifest
+// entries that no longer exist in the local source directory or whose
+// type has changed. Excluded entries are skipped. Paths are sorted in
+// reverse order, so that children precede their parent directories.
+func prunedManifestPaths(prevManifest map[string]string, entries []dirEntry, excludes []string) []string {
+	kinds := make(map[string]string, len(entries))
+	for _, e := range entries {
+		kinds[e.relPath] = e.kind()
+	}
+
+	var paths []string
+	for rel, value := range prevManifest {
+		kind, ok := kinds[rel]
+		if ok && kind == manifestKind(value) {
+			continue
+		}
+
+		if !ok && isExcluded(rel, excludes) {
+			continue
+		}
+
+		paths = append(paths, rel)
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths
+}
+
+// pruneInstanceDir removes entries of the previous manifest from the target
+// directory within the instance that no longer exist in the local source
+// directory or whose type has changed. Entries that were not uploaded are
+// left intact, therefore directories that are no longer present locally
+// are removed only if they are empty.
+func pruneInstanceDir(client *sftp.Client, targetPath string, prevManifest map[string]string, entries []dirEntry, excludes []string) error {
+	kinds := make(map[string]string, len(entries))
+	for _, e := range entries {
+		kinds[e.relPath] = e.kind()
+	}
+
+	for _, rel := range prunedManifestPaths(prevManifest, entries, excludes) {
+		remotePath := path.Join(targetPath, rel)
+
+		// Remove files, symlinks, and entries whose type has
+		// changed, which must make room for the new entry.
+		_, exists := kinds[rel]
+		if exists || manifestKind(prevManifest[rel]) != "directory" {
+			err := removeInstancePath(client, remotePath)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("Could not remove %q: %v", remotePath, err)
+			}
+
+			continue
+		}
+
+		err := client.RemoveDirectory(remotePath)
+		if err != nil && !os.IsNotExist(err) {
+			// Keep directories that contain entries which
+			// were not uploaded.
+			children, readErr := client.ReadDir(remotePath)
+			if readErr != nil || len(children) == 0 {
+				return fmt.Errorf("Could not remove %q: %v", remotePath, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeInstancePath removes the given path within the instance. Unlike
+// sftp.Client.RemoveAll, symbolic links are removed without following them.
+func removeInstancePath(client *sftp.Client, remotePath string) error {
+	info, err := client.Lstat(remotePath)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return client.RemoveAll(remotePath)
+	}
+
+	return client.Remove(remotePath)
+}
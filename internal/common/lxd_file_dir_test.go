@@ -0,0 +1,48 @@
+package common
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceDirManifest(t *testing.T) {
+	entries := []dirEntry{
+		{relPath: "bin", mode: fs.ModeDir | 0755},
+		{relPath: "bin/app", mode: 0755, sha256: "abc"},
+		{relPath: "current", mode: fs.ModeSymlink | 0777, linkTarget: "bin/app"},
+	}
+
+	assert.Equal(t, map[string]string{
+		"bin":     "directory:0755:",
+		"bin/app": "file:0755:abc",
+		"current": "symlink:0777:bin/app",
+	}, sourceDirManifest(entries))
+}
+
+func TestPrunedManifestPaths(t *testing.T) {
+	prevManifest := map[string]string{
+		"conf":          "directory:0755:",
+		"conf/app.yaml": "file:0644:abc",
+		"old":           "directory:0755:",
+		"old/a.txt":     "file:0644:def",
+		"old/b":         "directory:0755:",
+		"old/b/c.txt":   "file:0644:ghi",
+		"link":          "symlink:0777:conf",
+		"debug.map":     "file:0644:jkl",
+	}
+
+	entries := []dirEntry{
+		{relPath: "conf", mode: fs.ModeDir | 0755},
+		{relPath: "conf/app.yaml", mode: 0644, sha256: "xyz"},
+		{relPath: "link", mode: fs.ModeDir | 0755},
+	}
+
+	// Changed files are kept, excluded entries are left intact, and
+	// children precede their parent directories.
+	assert.Equal(t, []string{"old/b/c.txt", "old/b", "old/a.txt", "old", "link"}, prunedManifestPaths(prevManifest, entries, []string{"*.map"}))
+
+	// Nothing is removed without a previous manifest.
+	assert.Empty(t, prunedManifestPaths(nil, entries, nil))
+}
@@ -0,0 +1,94 @@
+package common
+
+import (
+	"fmt"
+	"net"
+	"slices"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// DefaultTargetNIC is the instance NIC used to resolve instance addresses
+// when no NIC is specified.
+const DefaultTargetNIC = "eth0"
+
+// AddressFamily returns the address family ("inet" or "inet6") of the
+// given IP address.
+func AddressFamily(address string) string {
+	ip := net.ParseIP(address)
+	if ip != nil && ip.To4() == nil {
+		return "inet6"
+	}
+
+	return "inet"
+}
+
+// ResolveInstanceAddresses returns the global addresses of the given NIC
+// of the instances with the given names or matching the given label
+// selector, keyed by the instance name. Instances without an address of
+// the given family (e.g. stopped instances) are skipped. An error is
+// returned if any of the named instances does not exist.
+func ResolveInstanceAddresses(server lxd.InstanceServer, names []string, selector map[string]string, nic string, family string) (map[string]string, error) {
+	if nic == "" {
+		nic = DefaultTargetNIC
+	}
+
+	instances, err := server.GetInstancesFull(api.InstanceTypeAny)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve instances: %w", err)
+	}
+
+	addresses := make(map[string]string)
+	found := make([]string, 0, len(names))
+	for _, inst := range instances {
+		if len(names) > 0 {
+			if !slices.Contains(names, inst.Name) {
+				continue
+			}
+
+			found = append(found, inst.Name)
+		}
+
+		if !MatchLabels(inst.ExpandedConfig, selector) || inst.State == nil {
+			continue
+		}
+
+		address := instanceNICAddress(inst.Instance, *inst.State, nic, family)
+		if address != "" {
+			addresses[inst.Name] = address
+		}
+	}
+
+	for _, name := range names {
+		if !slices.Contains(found, name) {
+			return nil, fmt.Errorf("Instance %q not found", name)
+		}
+	}
+
+	return addresses, nil
+}
+
+// instanceNICAddress returns the first global address of the given family
+// on the instance NIC. The NIC is identified by its device name, which may
+// differ from the interface name within the instance (e.g. in VMs).
+func instanceNICAddress(inst api.Instance, state api.InstanceState, nic string, family string) string {
+	hwaddr := inst.Config["volatile."+nic+".hwaddr"]
+	if hwaddr == "" {
+		return ""
+	}
+
+	for _, network := range state.Network {
+		if network.Hwaddr != hwaddr {
+			continue
+		}
+
+		for _, addr := range network.Addresses {
+			if addr.Family == family && addr.Scope == "global" {
+				return addr.Address
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressFamily(t *testing.T) {
+	assert.Equal(t, "inet", AddressFamily("10.0.0.1"))
+	assert.Equal(t, "inet6", AddressFamily("fd42::1"))
+	assert.Equal(t, "inet", AddressFamily("invalid"))
+}
+
+func TestInstanceNICAddress(t *testing.T) {
+	inst := api.Instance{
+		Config: map[string]string{
+			"volatile.eth0.hwaddr": "00:16:3e:00:00:01",
+			"volatile.eth1.hwaddr": "00:16:3e:00:00:02",
+		},
+	}
+
+	state := api.InstanceState{
+		Network: map[string]api.InstanceStateNetwork{
+			"enp5s0": {
+				Hwaddr: "00:16:3e:00:00:01",
+				Addresses: []api.InstanceStateNetworkAddress{
+					{Family: "inet6", Address: "fe80::1", Scope: "link"},
+					{Family: "inet", Address: "10.0.0.10", Scope: "global"},
+					{Family: "inet6", Address: "fd42::10", Scope: "global"},
+				},
+			},
+			"enp6s0": {
+				Hwaddr: "00:16:3e:00:00:02",
+				Addresses: []api.InstanceStateNetworkAddress{
+					{Family: "inet", Address: "10.0.1.10", Scope: "global"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, "10.0.0.10", instanceNICAddress(inst, state, "eth0", "inet"))
+	assert.Equal(t, "fd42::10", instanceNICAddress(inst, state, "eth0", "inet6"))
+	assert.Equal(t, "10.0.1.10", instanceNICAddress(inst, state, "eth1", "inet"))
+	assert.Equal(t, "", instanceNICAddress(inst, state, "eth1", "inet6"))
+	assert.Equal(t, "", instanceNICAddress(inst, state, "eth2", "inet"))
+}
@@ -0,0 +1,97 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// LabelConfigPrefix is the prefix of config keys used to store labels.
+// For example, label "role" is stored as config key "user.label.role".
+const LabelConfigPrefix = "user.label."
+
+// LabelsFromConfig extracts labels from the given config.
+func LabelsFromConfig(config map[string]string) map[string]string {
+	labels := make(map[string]string)
+	for k, v := range config {
+		name, ok := strings.CutPrefix(k, LabelConfigPrefix)
+		if ok && name != "" {
+			labels[name] = v
+		}
+	}
+
+	return labels
+}
+
+// LabelsToConfig converts labels into config keys.
+func LabelsToConfig(labels map[string]string) map[string]string {
+	config := make(map[string]string, len(labels))
+	for k, v := range labels {
+		config[LabelConfigPrefix+k] = v
+	}
+
+	return config
+}
+
+// StripLabels returns a copy of the config without the keys used to
+// store labels.
+func StripLabels(config map[string]string) map[string]string {
+	config = maps.Clone(config)
+	maps.DeleteFunc(config, func(k string, _ string) bool {
+		return strings.HasPrefix(k, LabelConfigPrefix)
+	})
+
+	return config
+}
+
+// ToLabelsMapType extracts labels from the given config and converts them
+// into types.Map. Null is returned if there are no labels and labels are
+// not set in the model, to avoid a plan diff.
+func ToLabelsMapType(ctx context.Context, config map[string]string, modelLabels types.Map) (types.Map, diag.Diagnostics) {
+	labels := LabelsFromConfig(config)
+	if len(labels) == 0 && modelLabels.IsNull() {
+		return types.MapNull(types.StringType), nil
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, labels)
+}
+
+// MatchLabels returns true if the labels stored in the given config match
+// all labels of the selector.
+func MatchLabels(config map[string]string, selector map[string]string) bool {
+	for k, v := range selector {
+		value, ok := config[LabelConfigPrefix+k]
+		if !ok || value != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NoLabelKeyValidator ensures config key is not used to store labels,
+// which are managed using the "labels" attribute.
+type NoLabelKeyValidator struct{}
+
+func (v NoLabelKeyValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("config key cannot have %q prefix", LabelConfigPrefix)
+}
+
+func (v NoLabelKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("config key cannot have `%s` prefix", LabelConfigPrefix)
+}
+
+func (v NoLabelKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	value := req.ConfigValue.ValueString()
+
+	if strings.HasPrefix(value, LabelConfigPrefix) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid config key",
+			fmt.Sprintf(`Config key cannot have %q prefix. Move it into the "labels" attribute as %q instead. Got: %q.`, LabelConfigPrefix, strings.TrimPrefix(value, LabelConfigPrefix), value),
+		)
+	}
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelsFromConfig(t *testing.T) {
+	config := map[string]string{
+		"user.label.role": "web",
+		"user.label.env":  "prod",
+		"user.label.":     "ignored",
+		"user.foo":        "bar",
+		"limits.cpu":      "2",
+	}
+
+	assert.Equal(t, map[string]string{"role": "web", "env": "prod"}, LabelsFromConfig(config))
+	assert.Equal(t, map[string]string{}, LabelsFromConfig(nil))
+}
+
+func TestLabelsToConfig(t *testing.T) {
+	labels := map[string]string{"role": "web", "env": "prod"}
+
+	assert.Equal(t, map[string]string{"user.label.role": "web", "user.label.env": "prod"}, LabelsToConfig(labels))
+	assert.Equal(t, labels, LabelsFromConfig(LabelsToConfig(labels)))
+}
+
+func TestStripLabels(t *testing.T) {
+	config := map[string]string{
+		"user.label.role": "web",
+		"user.foo":        "bar",
+	}
+
+	assert.Equal(t, map[string]string{"user.foo": "bar"}, StripLabels(config))
+
+	// Original config must not be modified.
+	assert.Len(t, config, 2)
+}
+
+func TestMatchLabels(t *testing.T) {
+	config := map[string]string{
+		"user.label.role": "web",
+		"user.label.env":  "prod",
+		"user.role":       "db",
+	}
+
+	tests := []struct {
+		Name     string
+		Selector map[string]string
+		Match    bool
+	}{
+		{Name: "Empty selector", Selector: nil, Match: true},
+		{Name: "Single label", Selector: map[string]string{"role": "web"}, Match: true},
+		{Name: "All labels", Selector: map[string]string{"role": "web", "env": "prod"}, Match: true},
+		{Name: "Value mismatch", Selector: map[string]string{"role": "db"}, Match: false},
+		{Name: "Missing label", Selector: map[string]string{"zone": "a"}, Match: false},
+		{Name: "Partial mismatch", Selector: map[string]string{"role": "web", "env": "dev"}, Match: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Match, MatchLabels(config, test.Selector))
+		})
+	}
+}
+
+func TestToLabelsMapType(t *testing.T) {
+	ctx := context.Background()
+
+	// No labels and labels not set in the model.
+	labels, diags := ToLabelsMapType(ctx, map[string]string{"user.foo": "bar"}, types.MapNull(types.StringType))
+	assert.False(t, diags.HasError())
+	assert.True(t, labels.IsNull())
+
+	// Labels present on the server.
+	labels, diags = ToLabelsMapType(ctx, map[string]string{"user.label.role": "web"}, types.MapNull(types.StringType))
+	assert.False(t, diags.HasError())
+	assert.Len(t, labels.Elements(), 1)
+	assert.Equal(t, types.StringValue("web"), labels.Elements()["role"])
+}
@@ -1,14 +1,30 @@
 package common
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
 	"golang.org/x/sync/singleflight"
 )
 
+// Modes of the config key validation.
+const (
+	ConfigValidationError   = "error"
+	ConfigValidationWarning = "warning"
+	ConfigValidationNone    = "none"
+)
+
 var (
 	metadataCache     = make(map[string]*api.MetadataConfiguration)
 	metadataCacheLock sync.RWMutex
@@ -53,3 +69,226 @@ func ServerMetadataConfiguration(name string, server lxd.InstanceServer) (*api.M
 
 	return meta, nil
 }
+
+// ServerConfigKeys returns definitions of the configuration keys of the given
+// metadata entity, such as "instance", "project", or "storage-zfs". If group
+// is not empty, only keys of that group (e.g. "volume-conf") are returned.
+// Nil is returned if the server does not provide the metadata configuration,
+// or if the entity or group is not found.
+func ServerConfigKeys(server lxd.InstanceServer, entity string, group string) (map[string]api.MetadataConfigKey, error) {
+	meta, err := serverMetadata(server)
+	if err != nil || meta == nil {
+		return nil, err
+	}
+
+	return metadataConfigKeys(meta, entity, group), nil
+}
+
+// serverMetadata returns the metadata configuration of the server, or nil
+// if the server does not provide it.
+func serverMetadata(server lxd.InstanceServer) (*api.MetadataConfiguration, error) {
+	if server.CheckExtension("metadata_configuration") != nil {
+		return nil, nil
+	}
+
+	apiServer, _, err := server.GetServer()
+	if err != nil {
+		return nil, err
+	}
+
+	// Use server version as metadata configuration cache key, as metadata
+	// configuration is the same across LXD servers with the same version.
+	return ServerMetadataConfiguration(apiServer.Environment.ServerVersion, server)
+}
+
+// metadataConfigKeys returns the key definitions of the given entity and
+// group from the metadata configuration. If group is empty, keys of all
+// groups are returned. Nil is returned if no keys are found.
+func metadataConfigKeys(meta *api.MetadataConfiguration, entity string, group string) map[string]api.MetadataConfigKey {
+	groups, ok := meta.Configs[entity]
+	if !ok {
+		return nil
+	}
+
+	keys := make(map[string]api.MetadataConfigKey)
+	for name, g := range groups {
+		if group != "" && name != group {
+			continue
+		}
+
+		for _, configKeys := range g.Keys {
+			for k, v := range configKeys {
+				keys[k] = v
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return keys
+}
+
+// ValidateConfigKeys validates keys and values of the given configuration
+// against the key definitions retrieved from the server metadata. Keys with
+// the "user." prefix are always accepted, and unknown values are not checked.
+// Depending on the mode, issues are reported either as errors or warnings.
+func ValidateConfigKeys(config types.Map, keys map[string]api.MetadataConfigKey, configPath path.Path, mode string) diag.Diagnostics {
+	return validateKeys(config, keys, configPath, mode, "config key")
+}
+
+// validateKeys validates keys and values of the given map against the key
+// definitions. The kind (e.g. "config key") is used in reported issues.
+func validateKeys(config types.Map, keys map[string]api.MetadataConfigKey, configPath path.Path, mode string, kind string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if mode == ConfigValidationNone || len(keys) == 0 || config.IsNull() || config.IsUnknown() {
+		return diags
+	}
+
+	report := func(key string, summary string, detail string) {
+		if mode == ConfigValidationWarning {
+			diags.AddAttributeWarning(configPath.AtMapKey(key), summary, detail)
+		} else {
+			diags.AddAttributeError(configPath.AtMapKey(key), summary, detail)
+		}
+	}
+
+	for _, k := range utils.SortMapKeys(config.Elements()) {
+		if strings.HasPrefix(k, "user.") {
+			continue
+		}
+
+		def, ok := findConfigKey(k, keys)
+		if !ok {
+			detail := fmt.Sprintf("The %s %q is not supported by the server.", kind, k)
+
+			suggestion := suggestConfigKey(k, keys)
+			if suggestion != "" {
+				detail += fmt.Sprintf(" Did you mean %q?", suggestion)
+			}
+
+			detail += ` Keys with the "user." prefix are always accepted. To allow keys that are not described by the server metadata, set "config_validation" to "warning" or "none" in the provider configuration.`
+
+			report(k, "Unknown "+kind, detail)
+			continue
+		}
+
+		valuable, ok := config.Elements()[k].(basetypes.StringValuable)
+		if !ok {
+			continue
+		}
+
+		value, _ := valuable.ToStringValue(context.Background())
+		if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+			continue
+		}
+
+		v := value.ValueString()
+		switch def.Type {
+		case "bool":
+			if !slices.Contains([]string{"true", "false", "yes", "no", "on", "off", "1", "0"}, strings.ToLower(v)) {
+				report(k, "Invalid value", fmt.Sprintf("Value of %s %q must be a boolean. Got: %q.", kind, k, v))
+			}
+
+		case "integer":
+			_, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				report(k, "Invalid value", fmt.Sprintf("Value of %s %q must be an integer. Got: %q.", kind, k, v))
+			}
+		}
+	}
+
+	return diags
+}
+
+// findConfigKey returns the definition of the given config key. Key
+// definitions may contain placeholders, such as "limits.kernel.[limit_name]"
+// or "volatile.<name>.hwaddr", which match any single key segment, and a
+// trailing "*", which matches any remaining key segments.
+func findConfigKey(key string, keys map[string]api.MetadataConfigKey) (api.MetadataConfigKey, bool) {
+	def, ok := keys[key]
+	if ok {
+		return def, true
+	}
+
+	for pattern, def := range keys {
+		if matchConfigKey(key, pattern) {
+			return def, true
+		}
+	}
+
+	return api.MetadataConfigKey{}, false
+}
+
+// matchConfigKey checks whether the config key matches the key pattern.
+func matchConfigKey(key string, pattern string) bool {
+	keyParts := strings.Split(key, ".")
+	patternParts := strings.Split(pattern, ".")
+
+	for i, p := range patternParts {
+		if p == "*" && i == len(patternParts)-1 {
+			return len(keyParts) > i
+		}
+
+		if i >= len(keyParts) {
+			return false
+		}
+
+		isPlaceholder := (strings.HasPrefix(p, "<") && strings.HasSuffix(p, ">")) ||
+			(strings.HasPrefix(p, "[") && strings.HasSuffix(p, "]"))
+
+		if !isPlaceholder && p != keyParts[i] {
+			return false
+		}
+	}
+
+	return len(keyParts) == len(patternParts)
+}
+
+// suggestConfigKey returns the known config key that is the most similar to
+// the given key, or an empty string if no key is similar enough.
+func suggestConfigKey(key string, keys map[string]api.MetadataConfigKey) string {
+	suggestion := ""
+	best := 0
+
+	for _, k := range utils.SortMapKeys(keys) {
+		// Skip key patterns.
+		if strings.ContainsAny(k, "<[*") {
+			continue
+		}
+
+		distance := utils.EditDistance(key, k)
+		if distance > 3 || distance > len(key)/2 {
+			continue
+		}
+
+		if suggestion == "" || distance < best {
+			suggestion = k
+			best = distance
+		}
+	}
+
+	return suggestion
+}
+
+// ValidateServerConfig validates the given configuration against the config
+// keys of the metadata entity and group retrieved from the server. If the
+// server metadata cannot be retrieved, a warning is returned instead, since
+// the server still validates the configuration when it is applied.
+func ValidateServerConfig(server lxd.InstanceServer, config types.Map, entity string, group string, mode string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if mode == ConfigValidationNone {
+		return diags
+	}
+
+	keys, err := ServerConfigKeys(server, entity, group)
+	if err != nil {
+		diags.AddWarning("Failed to retrieve server metadata configuration", fmt.Sprintf("Config keys cannot be validated: %v", err))
+		return diags
+	}
+
+	return ValidateConfigKeys(config, keys, path.Root("config"), mode)
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfigKeys = map[string]api.MetadataConfigKey{
+	"limits.memory":              {Type: "string"},
+	"limits.processes":           {Type: "integer"},
+	"security.nesting":           {Type: "bool"},
+	"limits.kernel.[limit_name]": {Type: "string"},
+	"volatile.<name>.hwaddr":     {Type: "string"},
+	"environment.*":              {Type: "string"},
+}
+
+func TestMatchConfigKey(t *testing.T) {
+	tests := []struct {
+		Key     string
+		Pattern string
+		Match   bool
+	}{
+		{Key: "limits.memory", Pattern: "limits.memory", Match: true},
+		{Key: "limits.memory", Pattern: "limits.cpu", Match: false},
+		{Key: "limits.kernel.nofile", Pattern: "limits.kernel.[limit_name]", Match: true},
+		{Key: "limits.kernel", Pattern: "limits.kernel.[limit_name]", Match: false},
+		{Key: "limits.kernel.nofile.x", Pattern: "limits.kernel.[limit_name]", Match: false},
+		{Key: "volatile.eth0.hwaddr", Pattern: "volatile.<name>.hwaddr", Match: true},
+		{Key: "environment.HTTP_PROXY", Pattern: "environment.*", Match: true},
+		{Key: "environment.a.b", Pattern: "environment.*", Match: true},
+		{Key: "environment", Pattern: "environment.*", Match: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Key+"/"+test.Pattern, func(t *testing.T) {
+			assert.Equal(t, test.Match, matchConfigKey(test.Key, test.Pattern))
+		})
+	}
+}
+
+func TestSuggestConfigKey(t *testing.T) {
+	assert.Equal(t, "limits.memory", suggestConfigKey("limit.memory", testConfigKeys))
+	assert.Equal(t, "security.nesting", suggestConfigKey("security.nestin", testConfigKeys))
+	assert.Equal(t, "", suggestConfigKey("boot.autostart", testConfigKeys))
+}
+
+func TestValidateConfigKeys(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Config   map[string]attr.Value
+		Mode     string
+		Errors   int
+		Warnings int
+	}{
+		{
+			Name: "Valid keys",
+			Config: map[string]attr.Value{
+				"limits.memory":         types.StringValue("1GiB"),
+				"limits.kernel.nofile":  types.StringValue("1024"),
+				"environment.LANG":      types.StringValue("C"),
+				"user.anything":         types.StringValue("value"),
+				"security.nesting":      types.StringValue("TRUE"),
+				"limits.processes":      types.StringUnknown(),
+				"user.unknown.user.key": types.StringNull(),
+			},
+			Mode: ConfigValidationError,
+		},
+		{
+			Name: "Unknown key",
+			Config: map[string]attr.Value{
+				"limit.memory": types.StringValue("1GiB"),
+			},
+			Mode:   ConfigValidationError,
+			Errors: 1,
+		},
+		{
+			Name: "Invalid values",
+			Config: map[string]attr.Value{
+				"security.nesting": types.StringValue("maybe"),
+				"limits.processes": types.StringValue("many"),
+			},
+			Mode:   ConfigValidationError,
+			Errors: 2,
+		},
+		{
+			Name: "Warning mode",
+			Config: map[string]attr.Value{
+				"limit.memory": types.StringValue("1GiB"),
+			},
+			Mode:     ConfigValidationWarning,
+			Warnings: 1,
+		},
+		{
+			Name: "Validation disabled",
+			Config: map[string]attr.Value{
+				"limit.memory": types.StringValue("1GiB"),
+			},
+			Mode: ConfigValidationNone,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			config := types.MapValueMust(types.StringType, test.Config)
+			diags := ValidateConfigKeys(config, testConfigKeys, path.Root("config"), test.Mode)
+			assert.Equal(t, test.Errors, diags.ErrorsCount())
+			assert.Equal(t, test.Warnings, diags.WarningsCount())
+		})
+	}
+}
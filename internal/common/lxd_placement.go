@@ -0,0 +1,124 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// PlacementPolicy describes how the cluster member of a new instance is
+// chosen.
+type PlacementPolicy struct {
+	// SpreadAcross restricts candidates to the given cluster members
+	// and cluster member groups (prefixed with "@"). If empty, all
+	// cluster members are candidates.
+	SpreadAcross []string
+
+	// PreferMembers are chosen over other candidates if eligible.
+	PreferMembers []string
+
+	// AvoidMembers are never chosen.
+	AvoidMembers []string
+
+	// AntiAffinityLabel and AntiAffinityValue exclude cluster members
+	// hosting an instance with the same label value.
+	AntiAffinityLabel string
+	AntiAffinityValue string
+}
+
+// PlacementDecision is the cluster member chosen for a new instance
+// along with a human readable reason.
+type PlacementDecision struct {
+	Member string
+	Reason string
+}
+
+// ChoosePlacement chooses the cluster member for a new instance from the
+// given cluster members and existing instances. Online candidates that
+// satisfy the policy are narrowed down to preferred members, if any, and
+// the member hosting the fewest instances is chosen. Ties are broken by
+// the member name.
+func ChoosePlacement(members []api.ClusterMember, instances []api.Instance, policy PlacementPolicy) (PlacementDecision, error) {
+	load := make(map[string]int)
+	conflicts := make(map[string]int)
+	for _, inst := range instances {
+		load[inst.Location]++
+
+		if policy.AntiAffinityLabel != "" && inst.ExpandedConfig[LabelConfigPrefix+policy.AntiAffinityLabel] == policy.AntiAffinityValue {
+			conflicts[inst.Location]++
+		}
+	}
+
+	candidates := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.Status != "Online" || slices.Contains(policy.AvoidMembers, member.ServerName) {
+			continue
+		}
+
+		if len(policy.SpreadAcross) > 0 && !matchPlacementMember(member, policy.SpreadAcross) {
+			continue
+		}
+
+		candidates = append(candidates, member.ServerName)
+	}
+
+	if len(candidates) == 0 {
+		return PlacementDecision{}, errors.New("No online cluster member satisfies spread_across and avoid_members")
+	}
+
+	if policy.AntiAffinityLabel != "" {
+		candidates = slices.DeleteFunc(candidates, func(name string) bool {
+			return conflicts[name] > 0
+		})
+
+		if len(candidates) == 0 {
+			return PlacementDecision{}, fmt.Errorf("All eligible cluster members host an instance with label %q set to %q", policy.AntiAffinityLabel, policy.AntiAffinityValue)
+		}
+	}
+
+	kind := "eligible"
+	preferred := slices.DeleteFunc(slices.Clone(candidates), func(name string) bool {
+		return !slices.Contains(policy.PreferMembers, name)
+	})
+
+	if len(preferred) > 0 {
+		candidates = preferred
+		kind = "preferred"
+	}
+
+	slices.SortFunc(candidates, func(a string, b string) int {
+		if load[a] != load[b] {
+			return load[a] - load[b]
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	member := candidates[0]
+	reason := fmt.Sprintf("Least loaded of %d %s cluster member(s), hosting %d instance(s)", len(candidates), kind, load[member])
+	if policy.AntiAffinityLabel != "" {
+		reason += fmt.Sprintf(", none with label %q set to %q", policy.AntiAffinityLabel, policy.AntiAffinityValue)
+	}
+
+	return PlacementDecision{Member: member, Reason: reason}, nil
+}
+
+// matchPlacementMember returns true if the cluster member matches any of
+// the given cluster member names or groups (prefixed with "@").
+func matchPlacementMember(member api.ClusterMember, targets []string) bool {
+	for _, target := range targets {
+		group, ok := strings.CutPrefix(target, "@")
+		if ok && slices.Contains(member.Groups, group) {
+			return true
+		}
+
+		if !ok && target == member.ServerName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChoosePlacement(t *testing.T) {
+	members := []api.ClusterMember{
+		{ServerName: "node1", Status: "Online", Groups: []string{"default", "fast"}},
+		{ServerName: "node2", Status: "Online", Groups: []string{"default", "fast"}},
+		{ServerName: "node3", Status: "Online", Groups: []string{"default"}},
+		{ServerName: "node4", Status: "Offline", Groups: []string{"default"}},
+	}
+
+	instances := []api.Instance{
+		{Name: "a", Location: "node1", ExpandedConfig: map[string]string{"user.label.role": "db"}},
+		{Name: "b", Location: "node1", ExpandedConfig: map[string]string{"user.label.role": "web"}},
+		{Name: "c", Location: "node2", ExpandedConfig: map[string]string{"user.label.role": "web"}},
+	}
+
+	tests := []struct {
+		Name   string
+		Policy PlacementPolicy
+		Member string
+		Error  bool
+	}{
+		{Name: "Least loaded", Policy: PlacementPolicy{}, Member: "node3"},
+		{Name: "Spread across group", Policy: PlacementPolicy{SpreadAcross: []string{"@fast"}}, Member: "node2"},
+		{Name: "Spread across members", Policy: PlacementPolicy{SpreadAcross: []string{"node1", "node2"}}, Member: "node2"},
+		{Name: "Avoid members", Policy: PlacementPolicy{AvoidMembers: []string{"node3"}}, Member: "node2"},
+		{Name: "Prefer members", Policy: PlacementPolicy{PreferMembers: []string{"node1"}}, Member: "node1"},
+		{Name: "Prefer avoided member", Policy: PlacementPolicy{PreferMembers: []string{"node1"}, AvoidMembers: []string{"node1"}}, Member: "node3"},
+		{Name: "Offline member", Policy: PlacementPolicy{SpreadAcross: []string{"node4"}}, Error: true},
+		{Name: "Anti-affinity", Policy: PlacementPolicy{SpreadAcross: []string{"@fast"}, AntiAffinityLabel: "role", AntiAffinityValue: "db"}, Member: "node2"},
+		{Name: "Anti-affinity unsatisfiable", Policy: PlacementPolicy{SpreadAcross: []string{"@fast"}, AntiAffinityLabel: "role", AntiAffinityValue: "web"}, Error: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			decision, err := ChoosePlacement(members, instances, test.Policy)
+			if test.Error {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.Member, decision.Member)
+			assert.NotEmpty(t, decision.Reason)
+		})
+	}
+}
@@ -7,11 +7,11 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
 	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
-	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
 )
 
 type InstanceDataSourceModel struct {
@@ -20,19 +20,22 @@ type InstanceDataSourceModel struct {
 	Remote  types.String `tfsdk:"remote"`
 
 	// Computed
-	Description types.String `tfsdk:"description"`
-	Type        types.String `tfsdk:"type"`
-	IPv4        types.String `tfsdk:"ipv4_address"`
-	IPv6        types.String `tfsdk:"ipv6_address"`
-	MAC         types.String `tfsdk:"mac_address"`
-	Location    types.String `tfsdk:"location"`
-	Status      types.String `tfsdk:"status"`
-	Ephemeral   types.Bool   `tfsdk:"ephemeral"`
-	Running     types.Bool   `tfsdk:"running"`
-	Profiles    types.List   `tfsdk:"profiles"`
-	Devices     types.Map    `tfsdk:"devices"`
-	Config      types.Map    `tfsdk:"config"`
-	Interfaces  types.Map    `tfsdk:"interfaces"`
+	Description     types.String `tfsdk:"description"`
+	Type            types.String `tfsdk:"type"`
+	IPv4            types.String `tfsdk:"ipv4_address"`
+	IPv6            types.String `tfsdk:"ipv6_address"`
+	MAC             types.String `tfsdk:"mac_address"`
+	Location        types.String `tfsdk:"location"`
+	Status          types.String `tfsdk:"status"`
+	Ephemeral       types.Bool   `tfsdk:"ephemeral"`
+	Running         types.Bool   `tfsdk:"running"`
+	Profiles        types.List   `tfsdk:"profiles"`
+	Devices         types.Map    `tfsdk:"devices"`
+	Config          types.Map    `tfsdk:"config"`
+	Labels          types.Map    `tfsdk:"labels"`
+	Interfaces      types.Map    `tfsdk:"interfaces"`
+	ExpandedConfig  types.Map    `tfsdk:"expanded_config"`
+	ExpandedDevices types.Map    `tfsdk:"expanded_devices"`
 }
 
 type InstanceDataSource struct {
@@ -61,109 +64,147 @@ func (d *InstanceDataSource) Schema(_ context.Context, req datasource.SchemaRequ
 			"remote": schema.StringAttribute{
 				Optional: true,
 			},
+		},
+	}
 
-			// Computed.
+	// Computed.
+	for k, v := range instanceDataSourceComputedAttributes() {
+		resp.Schema.Attributes[k] = v
+	}
+}
 
-			"description": schema.StringAttribute{
-				Computed: true,
-			},
+// instanceDataSourceComputedAttributes returns the computed attributes
+// describing an existing instance.
+func instanceDataSourceComputedAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"description": schema.StringAttribute{
+			Computed: true,
+		},
 
-			"type": schema.StringAttribute{
-				Computed: true,
-			},
+		"type": schema.StringAttribute{
+			Computed: true,
+		},
 
-			"ephemeral": schema.BoolAttribute{
-				Computed: true,
-			},
+		"ephemeral": schema.BoolAttribute{
+			Computed: true,
+		},
 
-			"running": schema.BoolAttribute{
-				Computed: true,
-			},
+		"running": schema.BoolAttribute{
+			Computed: true,
+		},
 
-			"profiles": schema.ListAttribute{
-				Computed:    true,
-				ElementType: types.StringType,
-			},
+		"profiles": schema.ListAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+
+		"config": schema.MapAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+
+		"labels": schema.MapAttribute{
+			Computed:    true,
+			Description: "Effective instance labels including profiles",
+			ElementType: types.StringType,
+		},
+
+		"devices": schema.MapNestedAttribute{
+			Computed: true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Computed: true,
+					},
 
-			"config": schema.MapAttribute{
-				Computed:    true,
-				ElementType: types.StringType,
+					"properties": schema.MapAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
+					},
+				},
 			},
+		},
 
-			"devices": schema.MapNestedAttribute{
-				Computed: true,
-				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"type": schema.StringAttribute{
-							Computed: true,
-						},
+		"expanded_config": schema.MapAttribute{
+			Computed:    true,
+			Description: "Effective instance configuration including profiles, excluding volatile keys",
+			ElementType: types.StringType,
+		},
 
-						"properties": schema.MapAttribute{
-							Computed:    true,
-							ElementType: types.StringType,
-						},
+		"expanded_devices": schema.MapNestedAttribute{
+			Computed:    true,
+			Description: "Effective instance devices including profiles",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Computed: true,
+					},
+
+					"properties": schema.MapAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
 					},
 				},
 			},
+		},
 
-			"interfaces": schema.MapNestedAttribute{
-				Computed:    true,
-				Description: "Map of the instance network interfaces",
-				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"name": schema.StringAttribute{
-							Computed: true,
-						},
+		"interfaces": schema.MapNestedAttribute{
+			Computed:    true,
+			Description: "Map of the instance network interfaces",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"name": schema.StringAttribute{
+						Computed: true,
+					},
 
-						"type": schema.StringAttribute{
-							Computed: true,
-						},
+					"type": schema.StringAttribute{
+						Computed: true,
+					},
 
-						"state": schema.StringAttribute{
-							Computed: true,
-						},
+					"state": schema.StringAttribute{
+						Computed: true,
+					},
+
+					"ips": schema.ListNestedAttribute{
+						Computed: true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"address": schema.StringAttribute{
+									Computed: true,
+								},
+
+								"family": schema.StringAttribute{
+									Computed: true,
+								},
 
-						"ips": schema.ListNestedAttribute{
-							Computed: true,
-							NestedObject: schema.NestedAttributeObject{
-								Attributes: map[string]schema.Attribute{
-									"address": schema.StringAttribute{
-										Computed: true,
-									},
-
-									"family": schema.StringAttribute{
-										Computed: true,
-									},
-
-									"scope": schema.StringAttribute{
-										Computed: true,
-									},
+								"scope": schema.StringAttribute{
+									Computed: true,
 								},
 							},
 						},
 					},
 				},
 			},
+		},
 
-			"ipv4_address": schema.StringAttribute{
-				Computed: true,
-			},
+		"ipv4_address": schema.StringAttribute{
+			Computed: true,
+		},
 
-			"ipv6_address": schema.StringAttribute{
-				Computed: true,
-			},
+		"ipv6_address": schema.StringAttribute{
+			Computed: true,
+		},
 
-			"mac_address": schema.StringAttribute{
-				Computed: true,
-			},
+		"mac_address": schema.StringAttribute{
+			Computed: true,
+		},
 
-			"location": schema.StringAttribute{
-				Computed: true,
-			},
+		"location": schema.StringAttribute{
+			Computed: true,
+		},
 
-			"status": schema.StringAttribute{
-				Computed: true,
-			},
+		"status": schema.StringAttribute{
+			Computed: true,
 		},
 	}
 }
@@ -213,84 +254,80 @@ func (d *InstanceDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
+	diags = state.fromInstance(ctx, *instance, *instanceState)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// fromInstance sets the computed attributes of the model from the given
+// instance and its state.
+func (m *InstanceDataSourceModel) fromInstance(ctx context.Context, instance api.Instance, instanceState api.InstanceState) diag.Diagnostics {
+	var respDiags diag.Diagnostics
+
 	// Set null values for IPv4, IPv6, and MAC addresses to ensure
 	// the computed value is always set.
-	state.IPv4 = types.StringNull()
-	state.IPv6 = types.StringNull()
-	state.MAC = types.StringNull()
-
-	accIface, ok := instance.ExpandedConfig["user.access_interface"]
-	if ok {
-		// If there is an user.access_interface set, extract IPv4, IPv6 and
-		// MAC addresses from that network interface.
-		net, ok := instanceState.Network[accIface]
-		if ok {
-			state.MAC = types.StringValue(net.Hwaddr)
-			ipv4, ipv6 := findGlobalIPAddresses(net)
-
-			if ipv4 != "" {
-				state.IPv4 = types.StringValue(ipv4)
-			}
-
-			if ipv6 != "" {
-				state.IPv6 = types.StringValue(ipv6)
-			}
-		}
-	} else {
-		// Search for the first interface (alphabetically sorted) that has
-		// global IPv4 or IPv6 address.
-		for _, iface := range utils.SortMapKeys(instanceState.Network) {
-			if iface == "lo" {
-				continue
-			}
-
-			net := instanceState.Network[iface]
-			ipv4, ipv6 := findGlobalIPAddresses(net)
-			if ipv4 != "" || ipv6 != "" {
-				state.MAC = types.StringValue(net.Hwaddr)
-
-				if ipv4 != "" {
-					state.IPv4 = types.StringValue(ipv4)
-				}
-
-				if ipv6 != "" {
-					state.IPv6 = types.StringValue(ipv6)
-				}
-
-				break
-			}
-		}
+	m.IPv4 = types.StringNull()
+	m.IPv6 = types.StringNull()
+	m.MAC = types.StringNull()
+
+	ipv4, ipv6, mac := findAccessAddresses(instance, instanceState)
+	if mac != "" {
+		m.MAC = types.StringValue(mac)
+	}
+
+	if ipv4 != "" {
+		m.IPv4 = types.StringValue(ipv4)
+	}
+
+	if ipv6 != "" {
+		m.IPv6 = types.StringValue(ipv6)
 	}
 
 	// Convert config, profiles, and devices into schema type.
-	config, diags := common.ToConfigMapType(ctx, common.ToNullableConfig(instance.Config), state.Config)
-	resp.Diagnostics.Append(diags...)
+	config, diags := common.ToConfigMapType(ctx, common.ToNullableConfig(instance.Config), m.Config)
+	respDiags.Append(diags...)
 
 	profiles, diags := ToProfileListType(ctx, instance.Profiles)
-	resp.Diagnostics.Append(diags...)
+	respDiags.Append(diags...)
 
 	devices, diags := common.ToDeviceMapType(ctx, instance.Devices)
-	resp.Diagnostics.Append(diags...)
+	respDiags.Append(diags...)
 
 	interfaces, diags := common.ToInterfaceMapType(ctx, instanceState.Network, instance.Config)
-	resp.Diagnostics.Append(diags...)
+	respDiags.Append(diags...)
 
-	if resp.Diagnostics.HasError() {
-		return
-	}
+	expandedConfig, diags := types.MapValueFrom(ctx, types.StringType, getExpandedConfig(instance))
+	respDiags.Append(diags...)
 
-	state.Name = types.StringValue(instance.Name)
-	state.Type = types.StringValue(instance.Type)
-	state.Description = types.StringValue(instance.Description)
-	state.Ephemeral = types.BoolValue(instance.Ephemeral)
-	state.Running = types.BoolValue(instanceState.Status == api.Running.String())
-	state.Location = types.StringValue(instance.Location)
-	state.Status = types.StringValue(instance.Status)
-	state.Profiles = profiles
-	state.Devices = devices
-	state.Interfaces = interfaces
-	state.Config = config
+	labels, diags := types.MapValueFrom(ctx, types.StringType, common.LabelsFromConfig(instance.ExpandedConfig))
+	respDiags.Append(diags...)
 
-	diags = resp.State.Set(ctx, &state)
-	resp.Diagnostics.Append(diags...)
+	expandedDevices, diags := common.ToDeviceMapType(ctx, getExpandedDevices(instance))
+	respDiags.Append(diags...)
+
+	if respDiags.HasError() {
+		return respDiags
+	}
+
+	m.Name = types.StringValue(instance.Name)
+	m.Type = types.StringValue(instance.Type)
+	m.Description = types.StringValue(instance.Description)
+	m.Ephemeral = types.BoolValue(instance.Ephemeral)
+	m.Running = types.BoolValue(instanceState.Status == api.Running.String())
+	m.Location = types.StringValue(instance.Location)
+	m.Status = types.StringValue(instance.Status)
+	m.Profiles = profiles
+	m.Devices = devices
+	m.Interfaces = interfaces
+	m.ExpandedConfig = expandedConfig
+	m.ExpandedDevices = expandedDevices
+	m.Config = config
+	m.Labels = labels
+
+	return respDiags
 }
@@ -0,0 +1,240 @@
+package instance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+// defaultInstanceFileMaxSize is the default maximum size of a file that is
+// read from an instance by the instance file data source (1 MiB).
+const defaultInstanceFileMaxSize int64 = 1024 * 1024
+
+type InstanceFileDataSourceModel struct {
+	Instance types.String `tfsdk:"instance"`
+	Path     types.String `tfsdk:"path"`
+	MaxSize  types.Int64  `tfsdk:"max_size"`
+	Project  types.String `tfsdk:"project"`
+	Remote   types.String `tfsdk:"remote"`
+
+	// Computed.
+	Type          types.String `tfsdk:"type"`
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	SHA256        types.String `tfsdk:"sha256"`
+	Size          types.Int64  `tfsdk:"size"`
+	UserID        types.Int64  `tfsdk:"uid"`
+	GroupID       types.Int64  `tfsdk:"gid"`
+	Mode          types.String `tfsdk:"mode"`
+	Entries       types.List   `tfsdk:"entries"`
+}
+
+type InstanceFileDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewInstanceFileDataSource() datasource.DataSource {
+	return &InstanceFileDataSource{}
+}
+
+func (d *InstanceFileDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instance_file", req.ProviderTypeName)
+}
+
+func (d *InstanceFileDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"path": schema.StringAttribute{
+				Required:    true,
+				Description: "Absolute path of the file or directory within the instance",
+				Validators: []validator.String{
+					absolutePathValidator{},
+				},
+			},
+
+			"max_size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum size of the file in bytes",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			// Computed.
+
+			"type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Type of the path (file, directory, or symlink)",
+			},
+
+			"content": schema.StringAttribute{
+				Computed:    true,
+				Description: "Content of the file (only if it is valid UTF-8)",
+			},
+
+			"content_base64": schema.StringAttribute{
+				Computed:    true,
+				Description: "Base64 encoded content of the file",
+			},
+
+			"sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 checksum of the file content",
+			},
+
+			"size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Size of the file in bytes",
+			},
+
+			"uid": schema.Int64Attribute{
+				Computed: true,
+			},
+
+			"gid": schema.Int64Attribute{
+				Computed: true,
+			},
+
+			"mode": schema.StringAttribute{
+				Computed: true,
+			},
+
+			"entries": schema.ListAttribute{
+				Computed:    true,
+				Description: "Names of the directory entries",
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *InstanceFileDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *InstanceFileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state InstanceFileDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := d.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Instance.ValueString()
+	filePath := state.Path.ValueString()
+
+	reader, file, err := server.GetInstanceFile(instanceName, filePath)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve file %q from instance %q", filePath, instanceName), err.Error())
+		return
+	}
+
+	if reader != nil {
+		defer func() { _ = reader.Close() }()
+	}
+
+	state.Type = types.StringValue(file.Type)
+	state.UserID = types.Int64Value(file.UID)
+	state.GroupID = types.Int64Value(file.GID)
+	state.Mode = types.StringValue(fmt.Sprintf("%04o", file.Mode))
+	state.Content = types.StringNull()
+	state.ContentBase64 = types.StringNull()
+	state.SHA256 = types.StringNull()
+	state.Size = types.Int64Null()
+	state.Entries = types.ListNull(types.StringType)
+
+	if file.Type == "directory" {
+		// Directory listing mode.
+		entries, diags := types.ListValueFrom(ctx, types.StringType, file.Entries)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		state.Entries = entries
+	} else if reader != nil {
+		maxSize := defaultInstanceFileMaxSize
+		if !state.MaxSize.IsNull() {
+			maxSize = state.MaxSize.ValueInt64()
+		}
+
+		// Read at most one byte over the limit to detect files that
+		// exceed the maximum size without reading them completely.
+		content, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to read file %q from instance %q", filePath, instanceName), err.Error())
+			return
+		}
+
+		if int64(len(content)) > maxSize {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("File %q in instance %q is too large", filePath, instanceName),
+				fmt.Sprintf("File exceeds the maximum size of %d bytes. Increase %q to read larger files.", maxSize, "max_size"),
+			)
+			return
+		}
+
+		checksum := sha256.Sum256(content)
+
+		state.ContentBase64 = types.StringValue(base64.StdEncoding.EncodeToString(content))
+		state.SHA256 = types.StringValue(hex.EncodeToString(checksum[:]))
+		state.Size = types.Int64Value(int64(len(content)))
+
+		// Binary content can only be consumed through its base64
+		// representation.
+		if utf8.Valid(content) {
+			state.Content = types.StringValue(string(content))
+		}
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
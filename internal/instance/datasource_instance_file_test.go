@@ -0,0 +1,122 @@
+package instance_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceFile_DS_basic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_DS_basic(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "instance", instanceName),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "path", "/foo/bar.txt"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "type", "file"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "content", "Hello, World!\n"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "content_base64", "SGVsbG8sIFdvcmxkIQo="),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "sha256", "c98c24b677eff44860afea6f493bbaec5bb1c4cbb209c6fc2bbb47f66ff2ad31"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "size", "14"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "uid", "1000"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "gid", "1000"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "mode", "0640"),
+					resource.TestCheckNoResourceAttr("data.lxd_instance_file.file", "entries"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceFile_DS_directory(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_DS_directory(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "path", "/foo"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "type", "directory"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "entries.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "entries.0", "bar.txt"),
+					resource.TestCheckNoResourceAttr("data.lxd_instance_file.dir", "content"),
+					resource.TestCheckNoResourceAttr("data.lxd_instance_file.dir", "sha256"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceFile_DS_maxSize(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstanceFile_DS_maxSize(instanceName),
+				ExpectError: regexp.MustCompile(`File exceeds the maximum size of 4 bytes`),
+			},
+		},
+	})
+}
+
+func testAccInstanceFile_DS_instance(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_file" "file1" {
+  instance           = lxd_instance.instance1.name
+  content            = "Hello, World!\n"
+  target_path        = "/foo/bar.txt"
+  uid                = 1000
+  gid                = 1000
+  mode               = "0640"
+  create_directories = true
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstanceFile_DS_basic(name string) string {
+	return testAccInstanceFile_DS_instance(name) + `
+data "lxd_instance_file" "file" {
+  instance = lxd_instance_file.file1.instance
+  path     = lxd_instance_file.file1.target_path
+}
+	`
+}
+
+func testAccInstanceFile_DS_directory(name string) string {
+	return testAccInstanceFile_DS_instance(name) + `
+data "lxd_instance_file" "dir" {
+  instance   = lxd_instance_file.file1.instance
+  path       = "/foo"
+  depends_on = [lxd_instance_file.file1]
+}
+	`
+}
+
+func testAccInstanceFile_DS_maxSize(name string) string {
+	return testAccInstanceFile_DS_instance(name) + `
+data "lxd_instance_file" "file" {
+  instance = lxd_instance_file.file1.instance
+  path     = lxd_instance_file.file1.target_path
+  max_size = 4
+}
+	`
+}
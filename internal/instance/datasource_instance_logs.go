@@ -0,0 +1,207 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+type InstanceLogsDataSourceModel struct {
+	Name      types.String `tfsdk:"name"`
+	LogFiles  types.Set    `tfsdk:"log_files"`
+	TailLines types.Int64  `tfsdk:"tail_lines"`
+	Project   types.String `tfsdk:"project"`
+	Remote    types.String `tfsdk:"remote"`
+
+	// Computed.
+	ConsoleLog types.String `tfsdk:"console_log"`
+	Available  types.List   `tfsdk:"available_log_files"`
+	Logs       types.Map    `tfsdk:"logs"`
+}
+
+type InstanceLogsDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewInstanceLogsDataSource() datasource.DataSource {
+	return &InstanceLogsDataSource{}
+}
+
+func (d *InstanceLogsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instance_logs", req.ProviderTypeName)
+}
+
+func (d *InstanceLogsDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"log_files": schema.SetAttribute{
+				Optional:    true,
+				Description: "Names of the log files to retrieve (e.g. lxc.log or qemu.log)",
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"tail_lines": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of trailing lines to return from each log",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			// Computed.
+
+			"console_log": schema.StringAttribute{
+				Computed:    true,
+				Description: "Console log of the instance",
+			},
+
+			"available_log_files": schema.ListAttribute{
+				Computed:    true,
+				Description: "Names of the log files available for the instance",
+				ElementType: types.StringType,
+			},
+
+			"logs": schema.MapAttribute{
+				Computed:    true,
+				Description: "Map of log file contents, keyed by log file name",
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *InstanceLogsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *InstanceLogsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state InstanceLogsDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	logFiles, diags := common.FromSetType[string](ctx, state.LogFiles)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := d.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Name.ValueString()
+	tail := int(state.TailLines.ValueInt64())
+
+	// The console log is not available for all instances, for example
+	// when the container has never been started.
+	consoleLog, err := getInstanceConsoleLog(server, instanceName)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(fmt.Sprintf("Instance %q not found", instanceName), err.Error())
+			return
+		}
+
+		resp.Diagnostics.AddWarning(fmt.Sprintf("Failed to retrieve console log of instance %q", instanceName), err.Error())
+	}
+
+	state.ConsoleLog = types.StringValue(tailLines(consoleLog, tail))
+
+	available, err := server.GetInstanceLogfiles(instanceName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve log files of instance %q", instanceName), err.Error())
+		return
+	}
+
+	slices.Sort(available)
+
+	// Retrieve all available log files if none are requested.
+	if state.LogFiles.IsNull() {
+		logFiles = available
+	}
+
+	logs := make(map[string]string, len(logFiles))
+	for _, filename := range logFiles {
+		if !slices.Contains(available, filename) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("log_files"),
+				fmt.Sprintf("Log file %q not found for instance %q", filename, instanceName),
+				fmt.Sprintf("Available log files: %v", available),
+			)
+
+			return
+		}
+
+		content, err := getInstanceLogfile(server, instanceName, filename)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve log file %q of instance %q", filename, instanceName), err.Error())
+			return
+		}
+
+		logs[filename] = tailLines(content, tail)
+	}
+
+	availableList, diags := types.ListValueFrom(ctx, types.StringType, available)
+	resp.Diagnostics.Append(diags...)
+
+	logsMap, diags := types.MapValueFrom(ctx, types.StringType, logs)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Available = availableList
+	state.Logs = logsMap
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,61 @@
+package instance_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceLogs_DS_basic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceLogs_DS_basic(instanceName, `["lxc.log"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_logs.inst", "name", instanceName),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_logs.inst", "console_log"),
+					resource.TestCheckTypeSetElemAttr("data.lxd_instance_logs.inst", "available_log_files.*", "lxc.log"),
+					resource.TestCheckResourceAttr("data.lxd_instance_logs.inst", "logs.%", "1"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_logs.inst", "logs.lxc.log"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceLogs_DS_invalidLogFile(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstanceLogs_DS_basic(instanceName, `["invalid.log"]`),
+				ExpectError: regexp.MustCompile(`Log file "invalid.log" not found`),
+			},
+		},
+	})
+}
+
+func testAccInstanceLogs_DS_basic(name string, logFiles string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "inst" {
+  name  = "%s"
+  image = "%s"
+}
+
+data "lxd_instance_logs" "inst" {
+  name       = lxd_instance.inst.name
+  log_files  = %s
+  tail_lines = 50
+}
+	`, name, acctest.TestImage, logFiles)
+}
@@ -0,0 +1,196 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+type InstanceSnapshotsDataSourceModel struct {
+	Instance   types.String `tfsdk:"instance"`
+	NameRegex  types.String `tfsdk:"name_regex"`
+	MostRecent types.Bool   `tfsdk:"most_recent"`
+	Project    types.String `tfsdk:"project"`
+	Remote     types.String `tfsdk:"remote"`
+
+	// Computed.
+	Snapshots []InstanceSnapshotDataModel `tfsdk:"snapshots"`
+}
+
+// InstanceSnapshotDataModel represents a single snapshot of the instance.
+type InstanceSnapshotDataModel struct {
+	Name      types.String `tfsdk:"name"`
+	CreatedAt types.Int64  `tfsdk:"created_at"`
+	ExpiresAt types.String `tfsdk:"expires_at"`
+	Stateful  types.Bool   `tfsdk:"stateful"`
+	Size      types.Int64  `tfsdk:"size"`
+}
+
+type InstanceSnapshotsDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewInstanceSnapshotsDataSource() datasource.DataSource {
+	return &InstanceSnapshotsDataSource{}
+}
+
+func (d *InstanceSnapshotsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instance_snapshots", req.ProviderTypeName)
+}
+
+func (d *InstanceSnapshotsDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression used to filter snapshots by name",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"most_recent": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to return only the most recent snapshot",
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			// Computed.
+
+			"snapshots": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Snapshots of the instance, ordered by creation time",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"created_at": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"expires_at": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"stateful": schema.BoolAttribute{
+							Computed: true,
+						},
+
+						"size": schema.Int64Attribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *InstanceSnapshotsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *InstanceSnapshotsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state InstanceSnapshotsDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if state.NameRegex.ValueString() != "" {
+		var err error
+		nameRegex, err = regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+			return
+		}
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := d.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Instance.ValueString()
+	snapshots, err := server.GetInstanceSnapshots(instanceName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve snapshots of instance %q", instanceName), err.Error())
+		return
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+
+	state.Snapshots = make([]InstanceSnapshotDataModel, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if nameRegex != nil && !nameRegex.MatchString(snapshot.Name) {
+			continue
+		}
+
+		// Zero time means the snapshot never expires.
+		expiresAt := types.StringNull()
+		if !snapshot.ExpiresAt.IsZero() {
+			expiresAt = types.StringValue(snapshot.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+
+		state.Snapshots = append(state.Snapshots, InstanceSnapshotDataModel{
+			Name:      types.StringValue(snapshot.Name),
+			CreatedAt: types.Int64Value(snapshot.CreatedAt.Unix()),
+			ExpiresAt: expiresAt,
+			Stateful:  types.BoolValue(snapshot.Stateful),
+			Size:      types.Int64Value(snapshot.Size),
+		})
+	}
+
+	if state.MostRecent.ValueBool() && len(state.Snapshots) > 0 {
+		state.Snapshots = state.Snapshots[len(state.Snapshots)-1:]
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
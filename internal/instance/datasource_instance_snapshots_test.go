@@ -0,0 +1,80 @@
+package instance_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceSnapshots_DS_basic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceSnapshots_DS_basic(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_snapshots.all", "snapshots.#", "3"),
+					resource.TestCheckResourceAttr("data.lxd_instance_snapshots.all", "snapshots.0.name", "nightly-1"),
+					resource.TestCheckResourceAttr("data.lxd_instance_snapshots.all", "snapshots.0.stateful", "false"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_snapshots.all", "snapshots.0.created_at"),
+					resource.TestCheckResourceAttr("data.lxd_instance_snapshots.all", "snapshots.0.expires_at", "2099-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttr("data.lxd_instance_snapshots.all", "snapshots.2.name", "manual"),
+					resource.TestCheckNoResourceAttr("data.lxd_instance_snapshots.all", "snapshots.2.expires_at"),
+					resource.TestCheckResourceAttr("data.lxd_instance_snapshots.nightly", "snapshots.#", "2"),
+					resource.TestCheckResourceAttr("data.lxd_instance_snapshots.latest", "snapshots.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_instance_snapshots.latest", "snapshots.0.name", "nightly-2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstanceSnapshots_DS_basic(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_snapshot" "nightly1" {
+  instance   = lxd_instance.instance1.name
+  name       = "nightly-1"
+  expires_at = "2099-01-01T00:00:00Z"
+}
+
+resource "lxd_instance_snapshot" "nightly2" {
+  instance   = lxd_instance.instance1.name
+  name       = "nightly-2"
+  depends_on = [lxd_instance_snapshot.nightly1]
+}
+
+resource "lxd_instance_snapshot" "manual" {
+  instance   = lxd_instance.instance1.name
+  name       = "manual"
+  depends_on = [lxd_instance_snapshot.nightly2]
+}
+
+data "lxd_instance_snapshots" "all" {
+  instance   = lxd_instance.instance1.name
+  depends_on = [lxd_instance_snapshot.manual]
+}
+
+data "lxd_instance_snapshots" "nightly" {
+  instance   = lxd_instance.instance1.name
+  name_regex = "^nightly-"
+  depends_on = [lxd_instance_snapshot.manual]
+}
+
+data "lxd_instance_snapshots" "latest" {
+  instance    = lxd_instance.instance1.name
+  name_regex  = "^nightly-"
+  most_recent = true
+  depends_on  = [lxd_instance_snapshot.manual]
+}
+	`, instanceName, acctest.TestImage)
+}
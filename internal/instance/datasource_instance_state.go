@@ -0,0 +1,300 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+type InstanceStateDataSourceModel struct {
+	Name    types.String `tfsdk:"name"`
+	Project types.String `tfsdk:"project"`
+	Remote  types.String `tfsdk:"remote"`
+
+	// Computed.
+	Status    types.String                                 `tfsdk:"status"`
+	PID       types.Int64                                  `tfsdk:"pid"`
+	Processes types.Int64                                  `tfsdk:"processes"`
+	CPUUsage  types.Int64                                  `tfsdk:"cpu_usage"`
+	Memory    *InstanceStateMemoryModel                    `tfsdk:"memory"`
+	Disks     map[string]InstanceStateDiskModel            `tfsdk:"disks"`
+	Network   map[string]InstanceStateNetworkCountersModel `tfsdk:"network"`
+}
+
+// InstanceStateMemoryModel represents the memory usage of the instance.
+type InstanceStateMemoryModel struct {
+	Usage         types.Int64 `tfsdk:"usage"`
+	UsagePeak     types.Int64 `tfsdk:"usage_peak"`
+	Total         types.Int64 `tfsdk:"total"`
+	SwapUsage     types.Int64 `tfsdk:"swap_usage"`
+	SwapUsagePeak types.Int64 `tfsdk:"swap_usage_peak"`
+}
+
+// InstanceStateDiskModel represents the usage of a single instance disk.
+type InstanceStateDiskModel struct {
+	Usage types.Int64 `tfsdk:"usage"`
+	Total types.Int64 `tfsdk:"total"`
+}
+
+// InstanceStateNetworkCountersModel represents the state and traffic
+// counters of a single instance network interface.
+type InstanceStateNetworkCountersModel struct {
+	HostName               types.String `tfsdk:"host_name"`
+	MAC                    types.String `tfsdk:"mac_address"`
+	MTU                    types.Int64  `tfsdk:"mtu"`
+	State                  types.String `tfsdk:"state"`
+	Type                   types.String `tfsdk:"type"`
+	BytesReceived          types.Int64  `tfsdk:"bytes_received"`
+	BytesSent              types.Int64  `tfsdk:"bytes_sent"`
+	PacketsReceived        types.Int64  `tfsdk:"packets_received"`
+	PacketsSent            types.Int64  `tfsdk:"packets_sent"`
+	ErrorsReceived         types.Int64  `tfsdk:"errors_received"`
+	ErrorsSent             types.Int64  `tfsdk:"errors_sent"`
+	PacketsDroppedInbound  types.Int64  `tfsdk:"packets_dropped_inbound"`
+	PacketsDroppedOutbound types.Int64  `tfsdk:"packets_dropped_outbound"`
+}
+
+type InstanceStateDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewInstanceStateDataSource() datasource.DataSource {
+	return &InstanceStateDataSource{}
+}
+
+func (d *InstanceStateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instance_state", req.ProviderTypeName)
+}
+
+func (d *InstanceStateDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			// Computed.
+
+			"status": schema.StringAttribute{
+				Computed: true,
+			},
+
+			"pid": schema.Int64Attribute{
+				Computed:    true,
+				Description: "PID of the instance's init process on the host",
+			},
+
+			"processes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of processes within the instance",
+			},
+
+			"cpu_usage": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Consumed CPU time in nanoseconds",
+			},
+
+			"memory": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "Memory usage in bytes",
+				Attributes: map[string]schema.Attribute{
+					"usage": schema.Int64Attribute{
+						Computed: true,
+					},
+
+					"usage_peak": schema.Int64Attribute{
+						Computed: true,
+					},
+
+					"total": schema.Int64Attribute{
+						Computed: true,
+					},
+
+					"swap_usage": schema.Int64Attribute{
+						Computed: true,
+					},
+
+					"swap_usage_peak": schema.Int64Attribute{
+						Computed: true,
+					},
+				},
+			},
+
+			"disks": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Map of disk usage in bytes, keyed by disk device name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"usage": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"total": schema.Int64Attribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"network": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Map of network interface counters, keyed by interface name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"host_name": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"mac_address": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"mtu": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"state": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"type": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"bytes_received": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"bytes_sent": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"packets_received": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"packets_sent": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"errors_received": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"errors_sent": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"packets_dropped_inbound": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"packets_dropped_outbound": schema.Int64Attribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *InstanceStateDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *InstanceStateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state InstanceStateDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := d.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Name.ValueString()
+	instanceState, _, err := server.GetInstanceState(instanceName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve state of instance %q", instanceName), err.Error())
+		return
+	}
+
+	state.Status = types.StringValue(instanceState.Status)
+	state.PID = types.Int64Value(instanceState.Pid)
+	state.Processes = types.Int64Value(instanceState.Processes)
+	state.CPUUsage = types.Int64Value(instanceState.CPU.Usage)
+	state.Memory = &InstanceStateMemoryModel{
+		Usage:         types.Int64Value(instanceState.Memory.Usage),
+		UsagePeak:     types.Int64Value(instanceState.Memory.UsagePeak),
+		Total:         types.Int64Value(instanceState.Memory.Total),
+		SwapUsage:     types.Int64Value(instanceState.Memory.SwapUsage),
+		SwapUsagePeak: types.Int64Value(instanceState.Memory.SwapUsagePeak),
+	}
+
+	state.Disks = make(map[string]InstanceStateDiskModel, len(instanceState.Disk))
+	for name, disk := range instanceState.Disk {
+		state.Disks[name] = InstanceStateDiskModel{
+			Usage: types.Int64Value(disk.Usage),
+			Total: types.Int64Value(disk.Total),
+		}
+	}
+
+	state.Network = make(map[string]InstanceStateNetworkCountersModel, len(instanceState.Network))
+	for name, net := range instanceState.Network {
+		state.Network[name] = InstanceStateNetworkCountersModel{
+			HostName:               types.StringValue(net.HostName),
+			MAC:                    types.StringValue(net.Hwaddr),
+			MTU:                    types.Int64Value(int64(net.Mtu)),
+			State:                  types.StringValue(net.State),
+			Type:                   types.StringValue(net.Type),
+			BytesReceived:          types.Int64Value(net.Counters.BytesReceived),
+			BytesSent:              types.Int64Value(net.Counters.BytesSent),
+			PacketsReceived:        types.Int64Value(net.Counters.PacketsReceived),
+			PacketsSent:            types.Int64Value(net.Counters.PacketsSent),
+			ErrorsReceived:         types.Int64Value(net.Counters.ErrorsReceived),
+			ErrorsSent:             types.Int64Value(net.Counters.ErrorsSent),
+			PacketsDroppedInbound:  types.Int64Value(net.Counters.PacketsDroppedInbound),
+			PacketsDroppedOutbound: types.Int64Value(net.Counters.PacketsDroppedOutbound),
+		}
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
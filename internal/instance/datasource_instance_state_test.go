@@ -0,0 +1,67 @@
+package instance_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceState_DS_running(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceState_DS_basic(instanceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_state.inst", "name", instanceName),
+					resource.TestCheckResourceAttr("data.lxd_instance_state.inst", "status", "Running"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.inst", "pid"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.inst", "processes"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.inst", "cpu_usage"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.inst", "memory.usage"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.inst", "disks.root.usage"),
+					resource.TestCheckResourceAttr("data.lxd_instance_state.inst", "network.lo.type", "loopback"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.inst", "network.eth0.bytes_received"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceState_DS_stopped(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceState_DS_basic(instanceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_state.inst", "status", "Stopped"),
+					resource.TestCheckResourceAttr("data.lxd_instance_state.inst", "processes", "0"),
+					resource.TestCheckResourceAttr("data.lxd_instance_state.inst", "network.%", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstanceState_DS_basic(name string, running bool) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "inst" {
+  name    = "%s"
+  image   = "%s"
+  running = %t
+}
+
+data "lxd_instance_state" "inst" {
+  name = lxd_instance.inst.name
+}
+	`, name, acctest.TestImage, running)
+}
@@ -89,6 +89,9 @@ func TestAccInstance_DS_device(t *testing.T) {
 					resource.TestCheckResourceAttr("data.lxd_instance.inst", "devices.shared.type", "disk"),
 					resource.TestCheckResourceAttr("data.lxd_instance.inst", "devices.shared.properties.path", "/tmp/shared"),
 					resource.TestCheckResourceAttr("data.lxd_instance.inst", "devices.shared.properties.source", "/tmp"),
+					resource.TestCheckResourceAttr("data.lxd_instance.inst", "expanded_devices.shared.type", "disk"),
+					resource.TestCheckResourceAttr("data.lxd_instance.inst", "expanded_devices.root.type", "disk"),
+					resource.TestCheckNoResourceAttr("data.lxd_instance.inst", "expanded_devices.shared.properties.user.managed-by"),
 				),
 			},
 		},
@@ -0,0 +1,318 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+type InstancesDataSourceModel struct {
+	Project     types.String `tfsdk:"project"`
+	AllProjects types.Bool   `tfsdk:"all_projects"`
+	Type        types.String `tfsdk:"type"`
+	Status      types.String `tfsdk:"status"`
+	Location    types.String `tfsdk:"location"`
+	Profiles    types.Set    `tfsdk:"profiles"`
+	NameRegex   types.String `tfsdk:"name_regex"`
+	UserConfig  types.Map    `tfsdk:"user_config"`
+	Selector    types.Map    `tfsdk:"selector"`
+	Remote      types.String `tfsdk:"remote"`
+
+	// Computed.
+	Instances []InstanceDataSourceModel `tfsdk:"instances"`
+}
+
+type InstancesDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewInstancesDataSource() datasource.DataSource {
+	return &InstancesDataSource{}
+}
+
+func (d *InstancesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instances", req.ProviderTypeName)
+}
+
+func (d *InstancesDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	// Instances expose the same attributes as the instance data source.
+	instanceAttributes := instanceDataSourceComputedAttributes()
+	instanceAttributes["name"] = schema.StringAttribute{
+		Computed: true,
+	}
+
+	instanceAttributes["project"] = schema.StringAttribute{
+		Computed: true,
+	}
+
+	instanceAttributes["remote"] = schema.StringAttribute{
+		Computed: true,
+	}
+
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"project": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"all_projects": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to list instances from all projects",
+				Validators: []validator.Bool{
+					boolvalidator.ConflictsWith(path.MatchRoot("project")),
+				},
+			},
+
+			"type": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("container", "virtual-machine"),
+				},
+			},
+
+			"status": schema.StringAttribute{
+				Optional:    true,
+				Description: "Status of the instances (e.g. Running or Stopped)",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"location": schema.StringAttribute{
+				Optional:    true,
+				Description: "Cluster member on which the instances are located",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"profiles": schema.SetAttribute{
+				Optional:    true,
+				Description: "Profiles that must be applied to the instances",
+				ElementType: types.StringType,
+			},
+
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression used to filter instances by name",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"user_config": schema.MapAttribute{
+				Optional:    true,
+				Description: "User configuration keys and values that the instances must match",
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(`^user\.`), `Key must start with "user."`),
+					),
+				},
+			},
+
+			"selector": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels that the instances must match, including labels inherited from profiles",
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			// Computed.
+
+			"instances": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Instances matching the filters, ordered by project and name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: instanceAttributes,
+				},
+			},
+		},
+	}
+}
+
+func (d *InstancesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *InstancesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state InstancesDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if state.NameRegex.ValueString() != "" {
+		var err error
+		nameRegex, err = regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+			return
+		}
+	}
+
+	profiles, diags := common.FromSetType[string](ctx, state.Profiles)
+	resp.Diagnostics.Append(diags...)
+
+	userConfig, diags := common.ToConfigMap(ctx, state.UserConfig)
+	resp.Diagnostics.Append(diags...)
+
+	selector, diags := common.ToConfigMap(ctx, state.Selector)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := d.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceType := api.InstanceType(state.Type.ValueString())
+	if instanceType == "" {
+		instanceType = api.InstanceTypeAny
+	}
+
+	// Use server-side filtering if supported. Filters are also applied
+	// below, as older servers do not support filtering. Status is matched
+	// only on the client side, as it is matched case-insensitively.
+	var filters []string
+	if server.HasExtension("api_filtering") && state.Location.ValueString() != "" {
+		filters = append(filters, "location="+state.Location.ValueString())
+	}
+
+	var instances []api.InstanceFull
+	if state.AllProjects.ValueBool() {
+		if len(filters) > 0 {
+			instances, err = server.GetInstancesFullAllProjectsWithFilter(instanceType, filters)
+		} else {
+			instances, err = server.GetInstancesFullAllProjects(instanceType)
+		}
+	} else {
+		if len(filters) > 0 {
+			instances, err = server.GetInstancesFullWithFilter(instanceType, filters)
+		} else {
+			instances, err = server.GetInstancesFull(instanceType)
+		}
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to retrieve instances", err.Error())
+		return
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Project != instances[j].Project {
+			return instances[i].Project < instances[j].Project
+		}
+
+		return instances[i].Name < instances[j].Name
+	})
+
+	state.Instances = make([]InstanceDataSourceModel, 0, len(instances))
+	for _, instance := range instances {
+		if !matchInstance(instance.Instance, state, nameRegex, profiles, userConfig, selector) {
+			continue
+		}
+
+		instanceState := api.InstanceState{}
+		if instance.State != nil {
+			instanceState = *instance.State
+		}
+
+		m := InstanceDataSourceModel{
+			Project: types.StringValue(instance.Project),
+			Remote:  state.Remote,
+			Config:  types.MapNull(types.StringType),
+		}
+
+		diags := m.fromInstance(ctx, instance.Instance, instanceState)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		state.Instances = append(state.Instances, m)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// matchInstance returns true if the instance matches all filters of
+// the instances data source.
+func matchInstance(instance api.Instance, filters InstancesDataSourceModel, nameRegex *regexp.Regexp, profiles []string, userConfig map[string]string, selector map[string]string) bool {
+	if !filters.Type.IsNull() && instance.Type != filters.Type.ValueString() {
+		return false
+	}
+
+	if !filters.Status.IsNull() && !strings.EqualFold(instance.Status, filters.Status.ValueString()) {
+		return false
+	}
+
+	if !filters.Location.IsNull() && instance.Location != filters.Location.ValueString() {
+		return false
+	}
+
+	if nameRegex != nil && !nameRegex.MatchString(instance.Name) {
+		return false
+	}
+
+	for _, profile := range profiles {
+		if !slices.Contains(instance.Profiles, profile) {
+			return false
+		}
+	}
+
+	for k, v := range userConfig {
+		value, ok := instance.ExpandedConfig[k]
+		if !ok || value != v {
+			return false
+		}
+	}
+
+	return common.MatchLabels(instance.ExpandedConfig, selector)
+}
@@ -0,0 +1,206 @@
+package instance_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstances_DS_filters(t *testing.T) {
+	prefix := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstances_DS_filters(prefix),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instances.all", "instances.#", "3"),
+					resource.TestCheckResourceAttr("data.lxd_instances.all", "instances.0.name", prefix+"-a"),
+					resource.TestCheckResourceAttr("data.lxd_instances.all", "instances.0.project", "default"),
+					resource.TestCheckResourceAttr("data.lxd_instances.all", "instances.0.status", "Running"),
+					resource.TestCheckResourceAttr("data.lxd_instances.all", "instances.0.config.user.role", "web"),
+					resource.TestCheckResourceAttrSet("data.lxd_instances.all", "instances.0.ipv4_address"),
+					resource.TestCheckResourceAttr("data.lxd_instances.running", "instances.#", "2"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.0.name", prefix+"-a"),
+					resource.TestCheckResourceAttr("data.lxd_instances.stopped_db", "instances.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_instances.stopped_db", "instances.0.name", prefix+"-c"),
+					resource.TestCheckResourceAttr("data.lxd_instances.none", "instances.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstances_DS_filters(prefix string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "a" {
+  name  = "%[1]s-a"
+  image = "%[2]s"
+
+  config = {
+    "user.role" = "web"
+  }
+}
+
+resource "lxd_instance" "b" {
+  name  = "%[1]s-b"
+  image = "%[2]s"
+
+  config = {
+    "user.role" = "db"
+  }
+}
+
+resource "lxd_instance" "c" {
+  name    = "%[1]s-c"
+  image   = "%[2]s"
+  running = false
+
+  config = {
+    "user.role" = "db"
+  }
+}
+
+locals {
+  name_regex = "^%[1]s-"
+}
+
+data "lxd_instances" "all" {
+  name_regex = local.name_regex
+  depends_on = [lxd_instance.a, lxd_instance.b, lxd_instance.c]
+}
+
+data "lxd_instances" "running" {
+  name_regex = local.name_regex
+  status     = "Running"
+  depends_on = [lxd_instance.a, lxd_instance.b, lxd_instance.c]
+}
+
+data "lxd_instances" "web" {
+  name_regex = local.name_regex
+  profiles   = ["default"]
+
+  user_config = {
+    "user.role" = "web"
+  }
+
+  depends_on = [lxd_instance.a, lxd_instance.b, lxd_instance.c]
+}
+
+data "lxd_instances" "stopped_db" {
+  name_regex = local.name_regex
+  status     = "stopped"
+  type       = "container"
+
+  user_config = {
+    "user.role" = "db"
+  }
+
+  depends_on = [lxd_instance.a, lxd_instance.b, lxd_instance.c]
+}
+
+data "lxd_instances" "none" {
+  name_regex = local.name_regex
+  type       = "virtual-machine"
+  depends_on = [lxd_instance.a, lxd_instance.b, lxd_instance.c]
+}
+	`, prefix, acctest.TestImage)
+}
+
+func TestAccInstances_DS_selector(t *testing.T) {
+	prefix := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstances_DS_selector(prefix),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.#", "2"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.0.name", prefix+"-a"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.0.labels.%", "2"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.0.labels.role", "web"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.0.labels.env", "prod"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.1.name", prefix+"-b"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web_dev", "instances.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web_dev", "instances.0.name", prefix+"-b"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstances_DS_selector(prefix string) string {
+	return fmt.Sprintf(`
+resource "lxd_profile" "prod" {
+  name = "%[1]s-prod"
+
+  labels = {
+    "env" = "prod"
+  }
+}
+
+resource "lxd_instance" "a" {
+  name     = "%[1]s-a"
+  image    = "%[2]s"
+  running  = false
+  profiles = ["default", lxd_profile.prod.name]
+
+  labels = {
+    "role" = "web"
+  }
+}
+
+resource "lxd_instance" "b" {
+  name    = "%[1]s-b"
+  image   = "%[2]s"
+  running = false
+
+  labels = {
+    "role" = "web"
+    "env"  = "dev"
+  }
+}
+
+resource "lxd_instance" "c" {
+  name    = "%[1]s-c"
+  image   = "%[2]s"
+  running = false
+
+  labels = {
+    "role" = "db"
+  }
+}
+
+locals {
+  name_regex = "^%[1]s-"
+}
+
+data "lxd_instances" "web" {
+  name_regex = local.name_regex
+
+  selector = {
+    "role" = "web"
+  }
+
+  depends_on = [lxd_instance.a, lxd_instance.b, lxd_instance.c]
+}
+
+data "lxd_instances" "web_dev" {
+  name_regex = local.name_regex
+
+  selector = {
+    "role" = "web"
+    "env"  = "dev"
+  }
+
+  depends_on = [lxd_instance.a, lxd_instance.b, lxd_instance.c]
+}
+	`, prefix, acctest.TestImage)
+}
@@ -0,0 +1,363 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+)
+
+// Access provisioning methods.
+const (
+	accessMethodCloudInit = "cloud-init"
+	accessMethodExec      = "exec"
+)
+
+// accessUserScript creates the access user (if missing) and its .ssh
+// directory, and prints the user's UID and GID. The login shell matches
+// the one set by cloud-init, unless bash is not available.
+const accessUserScript = `set -e
+if ! id -u "$ACCESS_USER" >/dev/null 2>&1; then
+  shell=/bin/sh
+  if [ -x /bin/bash ]; then
+    shell=/bin/bash
+  fi
+  if command -v useradd >/dev/null 2>&1; then
+    useradd -m -d "$ACCESS_HOME" -s "$shell" "$ACCESS_USER"
+  else
+    adduser -D -h "$ACCESS_HOME" -s "$shell" "$ACCESS_USER"
+  fi
+fi
+mkdir -p "$ACCESS_HOME/.ssh"
+chmod 700 "$ACCESS_HOME/.ssh"
+chown "$(id -u "$ACCESS_USER"):$(id -g "$ACCESS_USER")" "$ACCESS_HOME/.ssh"
+echo "$(id -u "$ACCESS_USER") $(id -g "$ACCESS_USER")"
+`
+
+// accessCloudInitScript waits for cloud-init to finish. It exits with
+// status 127 if cloud-init is not available within the instance.
+const accessCloudInitScript = `command -v cloud-init >/dev/null 2>&1 || exit 127
+cloud-init status --wait >/dev/null 2>&1
+exit 0
+`
+
+// AccessModel represents the access block.
+type AccessModel struct {
+	User              types.String `tfsdk:"user"`
+	SSHAuthorizedKeys types.List   `tfsdk:"ssh_authorized_keys"`
+	Sudo              types.Bool   `tfsdk:"sudo"`
+
+	// Computed.
+	Method types.String `tfsdk:"method"`
+}
+
+// keys returns the configured SSH authorized keys.
+func (m AccessModel) keys(ctx context.Context) ([]string, diag.Diagnostics) {
+	keys := make([]string, 0, len(m.SSHAuthorizedKeys.Elements()))
+	diags := m.SSHAuthorizedKeys.ElementsAs(ctx, &keys, false)
+	return keys, diags
+}
+
+// equal returns true if the configured attributes of both access blocks
+// are equal.
+func (m AccessModel) equal(o AccessModel) bool {
+	return m.User.Equal(o.User) && m.SSHAuthorizedKeys.Equal(o.SSHAuthorizedKeys) && m.Sudo.Equal(o.Sudo)
+}
+
+// ToAccessModel converts the access block into AccessModel. Nil is
+// returned if the block is not set.
+func ToAccessModel(ctx context.Context, accessObj types.Object) (*AccessModel, diag.Diagnostics) {
+	if accessObj.IsNull() || accessObj.IsUnknown() {
+		return nil, nil
+	}
+
+	var access AccessModel
+	diags := accessObj.As(ctx, &access, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	return &access, nil
+}
+
+// ToAccessObjectType converts AccessModel into the access block.
+func ToAccessObjectType(ctx context.Context, accessObj types.Object, access AccessModel) (types.Object, diag.Diagnostics) {
+	return types.ObjectValueFrom(ctx, accessObj.AttributeTypes(ctx), access)
+}
+
+// accessVendorData returns the cloud-init vendor data provisioning the
+// access user.
+func accessVendorData(ctx context.Context, access AccessModel) (string, diag.Diagnostics) {
+	keys, diags := access.keys(ctx)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	vendorData, err := common.AccessCloudConfig(access.User.ValueString(), keys, access.Sudo.ValueBool())
+	if err != nil {
+		diags.AddError("Failed to render access vendor data", err.Error())
+		return "", diags
+	}
+
+	return vendorData, nil
+}
+
+// profilesHaveVendorData returns true if any of the given profiles sets
+// cloud-init vendor data.
+func profilesHaveVendorData(server lxd.InstanceServer, profiles []string) (bool, error) {
+	for _, name := range profiles {
+		profile, _, err := server.GetProfile(name)
+		if err != nil {
+			return false, fmt.Errorf("Failed to retrieve profile %q: %w", name, err)
+		}
+
+		if common.HasVendorData(profile.Config) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// provisionAccess ensures the access user exists within the running
+// instance and has the configured SSH authorized keys. If cloudInit is
+// true, the keys are expected to be provisioned by cloud-init and exec
+// and file operations are used only if cloud-init is not available or
+// did not provision them. The configured keys are merged into the user's
+// authorized_keys file, and keys of the previous access block of the same
+// user that are no longer configured are removed. It returns the method
+// used.
+func provisionAccess(ctx context.Context, server lxd.InstanceServer, instanceName string, access AccessModel, previous *AccessModel, cloudInit bool) (string, diag.Diagnostics) {
+	keys, diags := access.keys(ctx)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	user := access.User.ValueString()
+
+	if cloudInit {
+		exec := accessExec(accessCloudInitScript, user, false)
+		diags := exec.Execute(ctx, server, instanceName)
+		if diags.HasError() {
+			return "", diags
+		}
+
+		if exec.ExitCode.ValueInt64() == 0 {
+			authorized, err := readAuthorizedKeys(server, instanceName, user)
+			if err == nil && len(common.PresentAuthorizedKeys(keys, authorized)) == len(keys) {
+				return accessMethodCloudInit, nil
+			}
+		}
+	}
+
+	// Create the user and its .ssh directory.
+	exec := accessExec(accessUserScript, user, true)
+	diags = exec.Execute(ctx, server, instanceName)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	var uid, gid int64
+	_, err := fmt.Sscanf(exec.Output.ValueString(), "%d %d", &uid, &gid)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to provision access user %q on instance %q", user, instanceName), fmt.Sprintf("Unexpected output %q: %v", exec.Output.ValueString(), err))
+		return "", diags
+	}
+
+	var revoked []string
+	if previous != nil && previous.User.Equal(access.User) {
+		revoked, diags = previous.keys(ctx)
+		if diags.HasError() {
+			return "", diags
+		}
+	}
+
+	content, _, err := readAuthorizedKeysFile(server, instanceName, user)
+	if err != nil && !errors.IsNotFoundError(err) {
+		diags.AddError(fmt.Sprintf("Failed to read SSH authorized keys from instance %q", instanceName), err.Error())
+		return "", diags
+	}
+
+	authorizedKeys := common.InstanceFileModel{
+		Content:    types.StringValue(common.MergeAuthorizedKeys(content, keys, revoked)),
+		TargetPath: types.StringValue(common.AccessAuthorizedKeysPath(user)),
+		UserID:     types.Int64Value(uid),
+		GroupID:    types.Int64Value(gid),
+		Mode:       types.StringValue("0600"),
+	}
+
+	err = common.InstanceFileUpload(server, instanceName, authorizedKeys)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to upload SSH authorized keys to instance %q", instanceName), err.Error())
+		return "", diags
+	}
+
+	// Root does not need a sudoers rule.
+	if user == "root" {
+		return accessMethodExec, nil
+	}
+
+	sudoersPath := common.AccessSudoersPath(user)
+	if access.Sudo.ValueBool() {
+		sudoers := common.InstanceFileModel{
+			Content:    types.StringValue(fmt.Sprintf("%s %s\n", user, common.AccessSudoRule)),
+			TargetPath: types.StringValue(sudoersPath),
+			Mode:       types.StringValue("0440"),
+		}
+
+		err = common.InstanceFileUpload(server, instanceName, sudoers)
+	} else {
+		err = common.InstanceFileDelete(server, instanceName, sudoersPath)
+	}
+
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to configure sudo for user %q on instance %q", user, instanceName), err.Error())
+		return "", diags
+	}
+
+	return accessMethodExec, nil
+}
+
+// revokeAccess removes the SSH authorized keys of the given access block
+// from the user's authorized_keys file within the instance, and removes
+// the sudoers file of the user. The user itself is not removed.
+func revokeAccess(ctx context.Context, server lxd.InstanceServer, instanceName string, access AccessModel) diag.Diagnostics {
+	keys, diags := access.keys(ctx)
+	if diags.HasError() {
+		return diags
+	}
+
+	user := access.User.ValueString()
+
+	content, file, err := readAuthorizedKeysFile(server, instanceName, user)
+	if err != nil && !errors.IsNotFoundError(err) {
+		diags.AddError(fmt.Sprintf("Failed to read SSH authorized keys from instance %q", instanceName), err.Error())
+		return diags
+	}
+
+	if err == nil {
+		authorizedKeys := common.InstanceFileModel{
+			Content:    types.StringValue(common.MergeAuthorizedKeys(content, nil, keys)),
+			TargetPath: types.StringValue(common.AccessAuthorizedKeysPath(user)),
+			UserID:     types.Int64Value(file.UID),
+			GroupID:    types.Int64Value(file.GID),
+			Mode:       types.StringValue(fmt.Sprintf("%04o", file.Mode)),
+		}
+
+		err = common.InstanceFileUpload(server, instanceName, authorizedKeys)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to remove SSH authorized keys of user %q from instance %q", user, instanceName), err.Error())
+			return diags
+		}
+	}
+
+	if user == "root" {
+		return diags
+	}
+
+	err = common.InstanceFileDelete(server, instanceName, common.AccessSudoersPath(user))
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to revoke sudo of user %q on instance %q", user, instanceName), err.Error())
+	}
+
+	return diags
+}
+
+// syncAccess updates the SSH authorized keys of the access block with
+// the configured keys that are present within the instance. This way,
+// keys removed from the instance are detected as drift. If the keys
+// cannot be read (e.g. the instance is stopped), the access block is
+// returned unchanged.
+func syncAccess(ctx context.Context, server lxd.InstanceServer, instanceName string, accessObj types.Object) (types.Object, diag.Diagnostics) {
+	access, diags := ToAccessModel(ctx, accessObj)
+	if diags.HasError() || access == nil || access.SSHAuthorizedKeys.IsUnknown() {
+		return accessObj, diags
+	}
+
+	keys, diags := access.keys(ctx)
+	if diags.HasError() {
+		return accessObj, diags
+	}
+
+	authorized, err := readAuthorizedKeys(server, instanceName, access.User.ValueString())
+	if err != nil {
+		if !errors.IsNotFoundError(err) {
+			return accessObj, nil
+		}
+
+		authorized = []string{}
+	}
+
+	present := common.PresentAuthorizedKeys(keys, authorized)
+	if len(present) == len(keys) {
+		return accessObj, nil
+	}
+
+	values := make([]attr.Value, 0, len(present))
+	for _, key := range present {
+		values = append(values, types.StringValue(key))
+	}
+
+	access.SSHAuthorizedKeys, diags = types.ListValue(types.StringType, values)
+	if diags.HasError() {
+		return accessObj, diags
+	}
+
+	return ToAccessObjectType(ctx, accessObj, *access)
+}
+
+// readAuthorizedKeys returns the keys of the access user's authorized_keys
+// file within the instance.
+func readAuthorizedKeys(server lxd.InstanceServer, instanceName string, user string) ([]string, error) {
+	content, _, err := readAuthorizedKeysFile(server, instanceName, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.ParseAuthorizedKeys(content), nil
+}
+
+// readAuthorizedKeysFile returns the content of the access user's
+// authorized_keys file within the instance, along with the file's owner
+// and mode.
+func readAuthorizedKeysFile(server lxd.InstanceServer, instanceName string, user string) (string, *lxd.InstanceFileResponse, error) {
+	reader, resp, err := server.GetInstanceFile(instanceName, common.AccessAuthorizedKeysPath(user))
+	if err != nil {
+		return "", nil, err
+	}
+
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return string(content), resp, nil
+}
+
+// accessExec returns the exec command running the given shell script as
+// root with the access user in its environment.
+func accessExec(script string, user string, failOnError bool) *common.ExecModel {
+	return &common.ExecModel{
+		Command: types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("/bin/sh"),
+			types.StringValue("-c"),
+			types.StringValue(script),
+		}),
+		Environment: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"ACCESS_USER": types.StringValue(user),
+			"ACCESS_HOME": types.StringValue(common.AccessHomeDir(user)),
+		}),
+		Enabled:      types.BoolValue(true),
+		RecordOutput: types.BoolValue(true),
+		FailOnError:  types.BoolValue(failOnError),
+	}
+}
@@ -0,0 +1,108 @@
+package instance
+
+import (
+	"fmt"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+)
+
+// getInstanceFacts returns facts about the instance that are available
+// within file templates. Keys match the attributes of the instance
+// resource, for example "{{ .name }}" or "{{ .ipv4_address }}".
+func getInstanceFacts(server lxd.InstanceServer, instanceName string) (map[string]any, error) {
+	instance, _, err := server.GetInstance(instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceState, _, err := server.GetInstanceState(instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	ipv4, ipv6, mac := findAccessAddresses(*instance, *instanceState)
+
+	config := getExpandedConfig(*instance)
+
+	// Interfaces are keyed by the device name, as in the "interfaces"
+	// attribute of the instance resource.
+	interfaces := make(map[string]any, len(instanceState.Network))
+	for name, net := range instanceState.Network {
+		deviceName := ""
+		for k, v := range instance.Config {
+			if strings.HasPrefix(k, "volatile.") && strings.HasSuffix(k, ".hwaddr") && v == net.Hwaddr {
+				deviceName = strings.SplitN(k, ".", 3)[1]
+				break
+			}
+		}
+
+		if deviceName == "" {
+			continue
+		}
+
+		ips := make([]map[string]string, 0, len(net.Addresses))
+		for _, addr := range net.Addresses {
+			ips = append(ips, map[string]string{
+				"address": addr.Address,
+				"family":  addr.Family,
+				"scope":   addr.Scope,
+			})
+		}
+
+		interfaces[deviceName] = map[string]any{
+			"name":  name,
+			"state": net.State,
+			"type":  net.Type,
+			"ips":   ips,
+		}
+	}
+
+	facts := map[string]any{
+		"name":         instance.Name,
+		"project":      instance.Project,
+		"location":     instance.Location,
+		"type":         instance.Type,
+		"status":       instance.Status,
+		"ipv4_address": ipv4,
+		"ipv6_address": ipv6,
+		"mac_address":  mac,
+		"config":       config,
+		"interfaces":   interfaces,
+	}
+
+	return facts, nil
+}
+
+// renderInstanceFiles renders templated files with the current facts of
+// the instance. Files that are not templates are returned unchanged. The
+// instance facts are retrieved only if there is at least one template.
+func renderInstanceFiles(server lxd.InstanceServer, instanceName string, files map[string]common.InstanceFileModel) (map[string]common.InstanceFileModel, error) {
+	var facts map[string]any
+
+	rendered := make(map[string]common.InstanceFileModel, len(files))
+	for k, f := range files {
+		if !f.IsTemplate() {
+			rendered[k] = f
+			continue
+		}
+
+		if facts == nil {
+			var err error
+			facts, err = getInstanceFacts(server, instanceName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to retrieve facts of instance %q: %v", instanceName, err)
+			}
+		}
+
+		r, err := common.RenderInstanceFile(f, facts)
+		if err != nil {
+			return nil, err
+		}
+
+		rendered[k] = r
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,62 @@
+package instance
+
+import (
+	"io"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+)
+
+// consoleLogTailLines is the number of console log lines included in
+// diagnostics when waiting for an instance fails.
+const consoleLogTailLines = 20
+
+// getInstanceConsoleLog returns the console log of the instance.
+func getInstanceConsoleLog(server lxd.InstanceServer, instanceName string) (string, error) {
+	reader, err := server.GetInstanceConsoleLog(instanceName, &lxd.InstanceConsoleLogArgs{})
+	if err != nil {
+		return "", err
+	}
+
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// getInstanceLogfile returns the content of the named log file of the
+// instance, such as "lxc.log" or "qemu.log".
+func getInstanceLogfile(server lxd.InstanceServer, instanceName string, filename string) (string, error) {
+	reader, err := server.GetInstanceLogfile(instanceName, filename)
+	if err != nil {
+		return "", err
+	}
+
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// tailLines returns the last n lines of the given text. If n is not
+// positive, the text is returned unchanged.
+func tailLines(text string, n int) string {
+	if n <= 0 {
+		return text
+	}
+
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) <= n {
+		return text
+	}
+
+	return strings.Join(lines[len(lines)-n:], "\n") + "\n"
+}
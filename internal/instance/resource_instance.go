@@ -3,8 +3,12 @@ package instance
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net/url"
+	"os"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,8 +16,10 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/units"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -23,13 +29,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/mitchellh/go-homedir"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
 	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
@@ -37,35 +46,61 @@ import (
 )
 
 type InstanceModel struct {
-	Name           types.String `tfsdk:"name"`
-	Description    types.String `tfsdk:"description"`
-	Type           types.String `tfsdk:"type"`
-	Image          types.String `tfsdk:"image"`
-	Ephemeral      types.Bool   `tfsdk:"ephemeral"`
-	Running        types.Bool   `tfsdk:"running"`
-	AllowRestart   types.Bool   `tfsdk:"allow_restart"`
-	WaitForConfigs types.Set    `tfsdk:"wait_for"`
-	Profiles       types.List   `tfsdk:"profiles"`
-	Devices        types.Set    `tfsdk:"device"`
-	Files          types.Set    `tfsdk:"file"`
-	Execs          types.Map    `tfsdk:"execs"`
-	Config         types.Map    `tfsdk:"config"`
-	Project        types.String `tfsdk:"project"`
-	Remote         types.String `tfsdk:"remote"`
-	Target         types.String `tfsdk:"target"`
+	Name             types.String `tfsdk:"name"`
+	Description      types.String `tfsdk:"description"`
+	Type             types.String `tfsdk:"type"`
+	Image            types.String `tfsdk:"image"`
+	SourceBackup     types.Object `tfsdk:"source_backup"`
+	Ephemeral        types.Bool   `tfsdk:"ephemeral"`
+	Running          types.Bool   `tfsdk:"running"`
+	AllowRestart     types.Bool   `tfsdk:"allow_restart"`
+	WaitForConfigs   types.Set    `tfsdk:"wait_for"`
+	Profiles         types.List   `tfsdk:"profiles"`
+	Devices          types.Set    `tfsdk:"device"`
+	UnmanagedDevices types.String `tfsdk:"unmanaged_devices"`
+	Files            types.Set    `tfsdk:"file"`
+	Execs            types.Map    `tfsdk:"execs"`
+	Config           types.Map    `tfsdk:"config"`
+	Labels           types.Map    `tfsdk:"labels"`
+	Project          types.String `tfsdk:"project"`
+	Remote           types.String `tfsdk:"remote"`
+	Target           types.String `tfsdk:"target"`
+	Placement        types.Object `tfsdk:"placement"`
+	Access           types.Object `tfsdk:"access"`
 
 	// Computed.
-	IPv4       types.String `tfsdk:"ipv4_address"`
-	IPv6       types.String `tfsdk:"ipv6_address"`
-	MAC        types.String `tfsdk:"mac_address"`
-	Location   types.String `tfsdk:"location"`
-	Status     types.String `tfsdk:"status"`
-	Interfaces types.Map    `tfsdk:"interfaces"`
+	IPv4            types.String `tfsdk:"ipv4_address"`
+	IPv6            types.String `tfsdk:"ipv6_address"`
+	MAC             types.String `tfsdk:"mac_address"`
+	Location        types.String `tfsdk:"location"`
+	Status          types.String `tfsdk:"status"`
+	Interfaces      types.Map    `tfsdk:"interfaces"`
+	ExpandedConfig  types.Map    `tfsdk:"expanded_config"`
+	ExpandedDevices types.Map    `tfsdk:"expanded_devices"`
 
 	// Timeouts.
 	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
+// SourceBackupModel represents the source_backup block.
+type SourceBackupModel struct {
+	Path         types.String `tfsdk:"path"`
+	Pool         types.String `tfsdk:"pool"`
+	NameOverride types.Bool   `tfsdk:"name_override"`
+}
+
+// PlacementModel represents the placement block.
+type PlacementModel struct {
+	SpreadAcross      types.Set    `tfsdk:"spread_across"`
+	AntiAffinityLabel types.String `tfsdk:"anti_affinity_label"`
+	PreferMembers     types.Set    `tfsdk:"prefer_members"`
+	AvoidMembers      types.Set    `tfsdk:"avoid_members"`
+
+	// Computed.
+	Member types.String `tfsdk:"member"`
+	Reason types.String `tfsdk:"reason"`
+}
+
 func (m InstanceModel) IsContainer() bool {
 	return m.Type.ValueString() == "container"
 }
@@ -76,9 +111,14 @@ func (m InstanceModel) IsVirtualMachine() bool {
 
 // WaitForModel represents a single wait_for block.
 type WaitForModel struct {
-	Type  types.String `tfsdk:"type"`
-	Delay types.String `tfsdk:"delay"`
-	Nic   types.String `tfsdk:"nic"`
+	Type     types.String `tfsdk:"type"`
+	Delay    types.String `tfsdk:"delay"`
+	Nic      types.String `tfsdk:"nic"`
+	Command  types.List   `tfsdk:"command"`
+	Port     types.Int64  `tfsdk:"port"`
+	Path     types.String `tfsdk:"path"`
+	Interval types.String `tfsdk:"interval"`
+	Timeout  types.String `tfsdk:"timeout"`
 }
 
 func (m WaitForModel) IsAgent() bool {
@@ -101,6 +141,24 @@ func (m WaitForModel) IsReady() bool {
 	return m.Type.ValueString() == "ready"
 }
 
+func (m WaitForModel) IsExec() bool {
+	return m.Type.ValueString() == "exec"
+}
+
+func (m WaitForModel) IsPort() bool {
+	return m.Type.ValueString() == "port"
+}
+
+func (m WaitForModel) IsFile() bool {
+	return m.Type.ValueString() == "file"
+}
+
+// IsProbe returns true if the wait_for condition is checked by
+// periodically probing the instance (exec, port, or file).
+func (m WaitForModel) IsProbe() bool {
+	return m.IsExec() || m.IsPort() || m.IsFile()
+}
+
 // InstanceResource represent LXD instance resource.
 type InstanceResource struct {
 	provider *provider_config.LxdProviderConfig
@@ -145,6 +203,9 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("source_backup")),
+				},
 			},
 
 			"ephemeral": schema.BoolAttribute{
@@ -169,6 +230,19 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 				Default:     booldefault.StaticBool(false),
 			},
 
+			"unmanaged_devices": schema.StringAttribute{
+				Description: "Policy for devices not managed by Terraform: ignore, warn, remove (default), or adopt.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						common.UnmanagedDevicesIgnore,
+						common.UnmanagedDevicesWarn,
+						common.UnmanagedDevicesRemove,
+						common.UnmanagedDevicesAdopt,
+					),
+				},
+			},
+
 			// If profiles are null, use "default" profile.
 			// If profiles lengeth is 0, no profiles are applied.
 			"profiles": schema.ListAttribute{
@@ -199,15 +273,29 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 
 			"target": schema.StringAttribute{
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("placement")),
+				},
 			},
 
 			"config": schema.MapAttribute{
 				Optional:    true,
 				Computed:    true,
+				ElementType: common.ConfigValueType{},
+				Default:     mapdefault.StaticValue(types.MapValueMust(common.ConfigValueType{}, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(configKeyValidator{}, common.NoLabelKeyValidator{}),
+				},
+			},
+
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels of the instance, stored as user.label.* config keys",
 				ElementType: types.StringType,
-				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
 				Validators: []validator.Map{
-					mapvalidator.KeysAre(configKeyValidator{}),
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					// Prevent empty values.
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
 				},
 			},
 
@@ -215,7 +303,7 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 				Description: "Map of commands to run within the instance",
 				Optional:    true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
+					Attributes: execSchemaAttributes(map[string]schema.Attribute{
 						"command": schema.ListAttribute{
 							Description: "Command to run within the instance",
 							Required:    true,
@@ -256,6 +344,7 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 								stringvalidator.OneOf(
 									common.ON_CHANGE.String(),
 									common.ON_START.String(),
+									common.ON_DESTROY.String(),
 									common.ONCE.String(),
 								),
 							},
@@ -282,38 +371,11 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 							Default:     booldefault.StaticBool(false),
 						},
 
-						"uid": schema.Int64Attribute{
-							Description: "The user ID for running command",
-							Optional:    true,
-						},
-
-						"gid": schema.Int64Attribute{
-							Description: "The group ID for running command",
-							Optional:    true,
-						},
-
-						// Computed.
-
-						"exit_code": schema.Int64Attribute{
-							Description: "Exit code of the command",
-							Computed:    true,
-						},
-
-						"stdout": schema.StringAttribute{
-							Description: "Command standard output (if recorded)",
-							Computed:    true,
-						},
-
-						"stderr": schema.StringAttribute{
-							Description: "Command standard error (if recorded)",
-							Computed:    true,
-						},
-
 						"run_count": schema.Int64Attribute{
 							Description: "Internal run count indicating how many times the command was executed",
 							Computed:    true,
 						},
-					},
+					}),
 				},
 				Validators: []validator.Map{
 					mapvalidator.KeysAre(
@@ -328,6 +390,29 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 
 			// Computed.
 
+			"expanded_config": schema.MapAttribute{
+				Computed:    true,
+				Description: "Effective instance configuration including profiles, excluding volatile keys",
+				ElementType: types.StringType,
+			},
+
+			"expanded_devices": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Effective instance devices including profiles",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"properties": schema.MapAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+
 			"interfaces": schema.MapNestedAttribute{
 				Computed:    true,
 				Description: "Map of the instance network interfaces",
@@ -399,68 +484,138 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 		},
 
 		Blocks: map[string]schema.Block{
-			"wait_for": schema.SetNestedBlock{
-				Description: "Wait for instance condition to be met once the instance is started.",
-				NestedObject: schema.NestedBlockObject{
-					Attributes: map[string]schema.Attribute{
-						"type": schema.StringAttribute{
-							Required: true,
-							Validators: []validator.String{
-								stringvalidator.OneOf(
-									"agent",
-									"delay",
-									"ipv4",
-									"ipv6",
-									"ready",
-								),
-							},
+			"source_backup": schema.SingleNestedBlock{
+				Description: "Create the instance from a local backup tarball.",
+				Attributes: map[string]schema.Attribute{
+					"path": schema.StringAttribute{
+						Optional:    true,
+						Description: "Local path of the backup tarball.",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
 						},
+					},
 
-						"delay": schema.StringAttribute{
-							Optional: true,
+					"pool": schema.StringAttribute{
+						Optional:    true,
+						Description: "Storage pool to restore the instance into.",
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
 						},
+					},
 
-						"nic": schema.StringAttribute{
-							Optional: true,
+					"name_override": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Whether to restore the instance under its configured name instead of the name recorded in the backup. Defaults to true.",
+					},
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"placement": schema.SingleNestedBlock{
+				Description: "Policy used to choose the cluster member of the instance on creation.",
+				Attributes: map[string]schema.Attribute{
+					"spread_across": schema.SetAttribute{
+						Description: "Cluster members or groups (prefixed with @) the instance can be placed on",
+						Optional:    true,
+						ElementType: types.StringType,
+						Validators: []validator.Set{
+							setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+						},
+					},
+
+					"anti_affinity_label": schema.StringAttribute{
+						Description: "Label of the instance that must not be shared with instances on the chosen cluster member",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+
+					"prefer_members": schema.SetAttribute{
+						Description: "Cluster members chosen over other candidates if eligible",
+						Optional:    true,
+						ElementType: types.StringType,
+						Validators: []validator.Set{
+							setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+						},
+					},
+
+					"avoid_members": schema.SetAttribute{
+						Description: "Cluster members the instance is never placed on",
+						Optional:    true,
+						ElementType: types.StringType,
+						Validators: []validator.Set{
+							setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+						},
+					},
+
+					// Computed.
+
+					"member": schema.StringAttribute{
+						Description: "Cluster member chosen on creation",
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+
+					"reason": schema.StringAttribute{
+						Description: "Reason why the cluster member was chosen",
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
 						},
 					},
 				},
 			},
 
-			"device": schema.SetNestedBlock{
-				Description: "Instance device",
-				NestedObject: schema.NestedBlockObject{
-					Attributes: map[string]schema.Attribute{
-						"name": schema.StringAttribute{
-							Required:    true,
-							Description: "Device name",
+			"access": schema.SingleNestedBlock{
+				Description: "User provisioned within the instance with SSH authorized keys.",
+				Attributes: map[string]schema.Attribute{
+					"user": schema.StringAttribute{
+						Description: "Name of the user",
+						Optional:    true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(
+								regexp.MustCompile(`^[a-z_][a-z0-9_-]*$`),
+								"Must be a valid user name",
+							),
 						},
+					},
 
-						"type": schema.StringAttribute{
-							Required:    true,
-							Description: "Device type",
-							Validators: []validator.String{
-								stringvalidator.OneOf(
-									"none", "disk", "nic", "unix-char",
-									"unix-block", "usb", "gpu", "infiniband",
-									"proxy", "unix-hotplug", "tpm", "pci",
-								),
-							},
+					"ssh_authorized_keys": schema.ListAttribute{
+						Description: "SSH public keys authorized to log in as the user",
+						Optional:    true,
+						ElementType: types.StringType,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+							listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
 						},
+					},
 
-						"properties": schema.MapAttribute{
-							Required:    true,
-							Description: "Device properties",
-							ElementType: types.StringType,
-							Validators: []validator.Map{
-								// Prevent empty values.
-								mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
-							},
+					"sudo": schema.BoolAttribute{
+						Description: "Whether the user is allowed to run any command with sudo without a password",
+						Optional:    true,
+					},
+
+					// Computed.
+
+					"method": schema.StringAttribute{
+						Description: "Method used to provision the user (cloud-init or exec)",
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
 						},
 					},
 				},
 			},
 
+			"wait_for": waitForSchemaBlock(),
+
+			"device": deviceSchemaBlock(),
+
 			"file": schema.SetNestedBlock{
 				Description: "Upload file to instance",
 				NestedObject: schema.NestedBlockObject{
@@ -502,6 +657,248 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 							Computed: true,
 							Default:  booldefault.StaticBool(false),
 						},
+
+						"source_dir": schema.StringAttribute{
+							Optional: true,
+						},
+
+						"exclude": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+
+						"template": schema.BoolAttribute{
+							Optional: true,
+						},
+
+						"skip_content_check": schema.BoolAttribute{
+							Optional: true,
+						},
+
+						"sha256": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"manifest": schema.MapAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// execSchemaAttributes adds the attributes shared by the instance execs
+// and the command blocks of the instance exec resource to the given
+// attributes, and returns them.
+func execSchemaAttributes(attributes map[string]schema.Attribute) map[string]schema.Attribute {
+	maps.Copy(attributes, map[string]schema.Attribute{
+		"uid": schema.Int64Attribute{
+			Description: "The user ID for running command",
+			Optional:    true,
+		},
+
+		"gid": schema.Int64Attribute{
+			Description: "The group ID for running command",
+			Optional:    true,
+		},
+
+		"stdin": schema.StringAttribute{
+			Description: "Content passed to the command's standard input",
+			Optional:    true,
+			Sensitive:   true,
+			Validators: []validator.String{
+				stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("stdin_file")),
+			},
+		},
+
+		"stdin_file": schema.StringAttribute{
+			Description: "Path to a local file passed to the command's standard input",
+			Optional:    true,
+			Validators: []validator.String{
+				stringvalidator.LengthAtLeast(1),
+			},
+		},
+
+		"timeout": schema.StringAttribute{
+			Description: "Maximum duration of a single command run",
+			Optional:    true,
+			Validators: []validator.String{
+				durationValidator{},
+			},
+		},
+
+		"retries": schema.Int64Attribute{
+			Description: "Number of times the command is retried if it fails",
+			Optional:    true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		},
+
+		"retry_interval": schema.StringAttribute{
+			Description: "Time to wait between command retries",
+			Optional:    true,
+			Validators: []validator.String{
+				durationValidator{},
+			},
+		},
+
+		"max_output_bytes": schema.Int64Attribute{
+			Description: "Maximum number of recorded bytes of stdout and stderr each",
+			Optional:    true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+
+		"sensitive_output": schema.BoolAttribute{
+			Description: "Whether to record command's output in sensitive attributes",
+			Optional:    true,
+		},
+
+		// Computed.
+
+		"exit_code": schema.Int64Attribute{
+			Description: "Exit code of the command",
+			Computed:    true,
+		},
+
+		"stdout": schema.StringAttribute{
+			Description: "Command standard output (if recorded)",
+			Computed:    true,
+		},
+
+		"stderr": schema.StringAttribute{
+			Description: "Command standard error (if recorded)",
+			Computed:    true,
+		},
+
+		"sensitive_stdout": schema.StringAttribute{
+			Description: "Command standard output (if recorded and sensitive)",
+			Computed:    true,
+			Sensitive:   true,
+		},
+
+		"sensitive_stderr": schema.StringAttribute{
+			Description: "Command standard error (if recorded and sensitive)",
+			Computed:    true,
+			Sensitive:   true,
+		},
+	})
+
+	return attributes
+}
+
+// waitForSchemaBlock returns the schema of the wait_for block shared by
+// the instance and instance group resources.
+func waitForSchemaBlock() schema.SetNestedBlock {
+	return schema.SetNestedBlock{
+		Description: "Wait for instance condition to be met once the instance is started.",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"type": schema.StringAttribute{
+					Required: true,
+					Validators: []validator.String{
+						stringvalidator.OneOf(
+							"agent",
+							"delay",
+							"exec",
+							"file",
+							"ipv4",
+							"ipv6",
+							"port",
+							"ready",
+						),
+					},
+				},
+
+				"delay": schema.StringAttribute{
+					Optional: true,
+				},
+
+				"nic": schema.StringAttribute{
+					Optional: true,
+				},
+
+				"command": schema.ListAttribute{
+					Description: "Command that must succeed when type is exec",
+					Optional:    true,
+					ElementType: types.StringType,
+					Validators: []validator.List{
+						listvalidator.SizeAtLeast(1),
+					},
+				},
+
+				"port": schema.Int64Attribute{
+					Description: "TCP port that must be listening when type is port",
+					Optional:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(1, 65535),
+					},
+				},
+
+				"path": schema.StringAttribute{
+					Description: "Path that must exist when type is file",
+					Optional:    true,
+					Validators: []validator.String{
+						stringvalidator.LengthAtLeast(1),
+					},
+				},
+
+				"interval": schema.StringAttribute{
+					Description: "Interval between probes when type is exec, port, or file",
+					Optional:    true,
+					Validators: []validator.String{
+						durationValidator{positive: true},
+					},
+				},
+
+				"timeout": schema.StringAttribute{
+					Description: "Maximum time to wait for the probe to succeed when type is exec, port, or file",
+					Optional:    true,
+					Validators: []validator.String{
+						durationValidator{positive: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+// deviceSchemaBlock returns the schema of the device block shared by the
+// instance and instance group resources.
+func deviceSchemaBlock() schema.SetNestedBlock {
+	return schema.SetNestedBlock{
+		Description: "Instance device",
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"name": schema.StringAttribute{
+					Required:    true,
+					Description: "Device name",
+				},
+
+				"type": schema.StringAttribute{
+					Required:    true,
+					Description: "Device type",
+					Validators: []validator.String{
+						stringvalidator.OneOf(
+							"none", "disk", "nic", "unix-char",
+							"unix-block", "usb", "gpu", "infiniband",
+							"proxy", "unix-hotplug", "tpm", "pci",
+						),
+					},
+				},
+
+				"properties": schema.MapAttribute{
+					Required:    true,
+					Description: "Device properties",
+					ElementType: types.StringType,
+					Validators: []validator.Map{
+						// Prevent empty values.
+						mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
 					},
 				},
 			},
@@ -536,6 +933,212 @@ func (r *InstanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPl
 	if !req.Config.Raw.IsNull() && config.Profiles.IsNull() {
 		resp.Plan.SetAttribute(ctx, path.Root("profiles"), []string{"default"})
 	}
+
+	if !req.Plan.Raw.IsNull() {
+		// Facts of an existing instance are used to render templated
+		// files. They are retrieved lazily, only if needed. If the
+		// instance is being updated, facts may change and templated
+		// files are rendered again during apply.
+		getFacts := func() map[string]any {
+			if req.State.Raw.IsNull() || !req.Plan.Raw.Equal(req.State.Raw) || r.provider == nil {
+				return nil
+			}
+
+			var state InstanceModel
+			diags := req.State.Get(ctx, &state)
+			if diags.HasError() {
+				return nil
+			}
+
+			server, err := r.provider.InstanceServer(state.Remote.ValueString(), state.Project.ValueString(), "")
+			if err != nil {
+				return nil
+			}
+
+			facts, err := getInstanceFacts(server, state.Name.ValueString())
+			if err != nil {
+				return nil
+			}
+
+			return facts
+		}
+
+		resp.Diagnostics.Append(planFileChecksums(ctx, req.Plan, getFacts, resp)...)
+		resp.Diagnostics.Append(r.validatePlanConfig(ctx, req)...)
+		resp.Diagnostics.Append(r.warnUnmanagedDevices(ctx, req)...)
+	}
+}
+
+// warnUnmanagedDevices reports devices of an existing instance that are
+// neither managed by Terraform nor declared in the configuration, if the
+// unmanaged devices policy is "warn".
+func (r *InstanceResource) warnUnmanagedDevices(ctx context.Context, req resource.ModifyPlanRequest) diag.Diagnostics {
+	var plan, state InstanceModel
+
+	if r.provider == nil || req.State.Raw.IsNull() {
+		return nil
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	if diags.HasError() || plan.UnmanagedDevices.ValueString() != common.UnmanagedDevicesWarn {
+		return diags
+	}
+
+	diags = req.State.Get(ctx, &state)
+	if diags.HasError() {
+		return diags
+	}
+
+	declared, diags := common.ToDeviceMap(ctx, plan.Devices)
+	if diags.HasError() {
+		return diags
+	}
+
+	server, err := r.provider.InstanceServer(state.Remote.ValueString(), state.Project.ValueString(), "")
+	if err != nil {
+		return nil
+	}
+
+	instanceName := state.Name.ValueString()
+	instance, _, err := server.GetInstance(instanceName)
+	if err != nil {
+		return nil
+	}
+
+	return common.UnmanagedDevicesWarnings(fmt.Sprintf("instance %q", instanceName), instance.Devices, declared)
+}
+
+// validatePlanConfig validates the planned instance config keys and device
+// properties against the server metadata. Validation is skipped for config
+// and devices that are unchanged.
+func (r *InstanceResource) validatePlanConfig(ctx context.Context, req resource.ModifyPlanRequest) diag.Diagnostics {
+	var plan, state InstanceModel
+
+	if r.provider == nil || r.provider.ConfigValidation() == common.ConfigValidationNone {
+		return nil
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	if diags.HasError() {
+		return diags
+	}
+
+	configChanged := true
+	devicesChanged := true
+
+	if !req.State.Raw.IsNull() {
+		diags := req.State.Get(ctx, &state)
+		if diags.HasError() {
+			return diags
+		}
+
+		configChanged = !plan.Config.Equal(state.Config)
+		devicesChanged = !plan.Devices.Equal(state.Devices)
+	}
+
+	if (!configChanged && !devicesChanged) || plan.Remote.IsUnknown() {
+		return nil
+	}
+
+	// Config keys and device properties do not depend on the project.
+	server, err := r.provider.InstanceServer(plan.Remote.ValueString(), "", "")
+	if err != nil {
+		return nil
+	}
+
+	mode := r.provider.ConfigValidation()
+
+	if configChanged {
+		diags.Append(common.ValidateServerConfig(server, plan.Config, "instance", "", mode)...)
+	}
+
+	if devicesChanged {
+		diags.Append(common.ValidateDevices(ctx, server, plan.Devices, path.Root("device"), plan.Type.ValueString(), mode)...)
+	}
+
+	return diags
+}
+
+// planFileChecksums computes checksums of the local content of the files,
+// as well as manifests of uploaded directories, and sets them in the plan.
+// This way, the difference between the file content within the instance
+// and the local content is detected. Templates
+// are rendered with the facts returned by getFacts. If facts are not
+// available, checksums of templated files remain unknown.
+func planFileChecksums(ctx context.Context, plan tfsdk.Plan, getFacts func() map[string]any, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var files types.Set
+
+	diags := plan.GetAttribute(ctx, path.Root("file"), &files)
+	if diags.HasError() || files.IsNull() || files.IsUnknown() {
+		return diags
+	}
+
+	fileMap, diags := common.ToFileMap(ctx, files)
+	if diags.HasError() {
+		return diags
+	}
+
+	var facts map[string]any
+	var factsFetched bool
+
+	for k, f := range fileMap {
+		// Manifest is recorded only for directory uploads.
+		if !f.IsDir() && !f.SourceDir.IsUnknown() {
+			f.Manifest = types.MapNull(types.StringType)
+			fileMap[k] = f
+		}
+
+		if f.Content.IsUnknown() || f.SourcePath.IsUnknown() {
+			continue
+		}
+
+		rendered := f
+		if f.IsTemplate() {
+			if !factsFetched {
+				facts = getFacts()
+				factsFetched = true
+			}
+
+			// Checksum of the rendered template is known only
+			// after apply.
+			f.SHA256 = types.StringUnknown()
+			fileMap[k] = f
+
+			if facts == nil {
+				continue
+			}
+
+			var err error
+			rendered, err = common.RenderInstanceFile(f, facts)
+			if err != nil {
+				continue
+			}
+		}
+
+		// Source file may not exist until apply, in which case
+		// the checksum remains unknown.
+		hash, err := common.InstanceFileHash(rendered)
+		if err != nil {
+			continue
+		}
+
+		f.SHA256 = types.StringValue(hash)
+		if f.IsDir() {
+			f.Manifest, err = common.InstanceDirManifest(f)
+			if err != nil {
+				f.Manifest = types.MapUnknown(types.StringType)
+			}
+		}
+
+		fileMap[k] = f
+	}
+
+	fileSet, diags := common.ToFileSetType(ctx, fileMap)
+	if diags.HasError() {
+		return diags
+	}
+
+	return resp.Plan.SetAttribute(ctx, path.Root("file"), fileSet)
 }
 
 func (r InstanceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
@@ -571,8 +1174,65 @@ func (r InstanceResource) ValidateConfig(ctx context.Context, req resource.Valid
 		)
 	}
 
+	// Backup source requires the path of the backup tarball.
+	var sourceBackup *SourceBackupModel
+	if !config.SourceBackup.IsNull() && !config.SourceBackup.IsUnknown() {
+		resp.Diagnostics.Append(config.SourceBackup.As(ctx, &sourceBackup, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if sourceBackup.Path.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("source_backup").AtName("path"),
+				"Missing backup path",
+				`The "path" attribute is required within the "source_backup" block.`,
+			)
+		}
+	}
+
+	// Access requires the user and its SSH authorized keys.
+	access, diags := ToAccessModel(ctx, config.Access)
+	resp.Diagnostics.Append(diags...)
+	if access != nil {
+		if access.User.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("access").AtName("user"),
+				"Missing access user",
+				`The "user" attribute is required within the "access" block.`,
+			)
+		}
+
+		if access.SSHAuthorizedKeys.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("access").AtName("ssh_authorized_keys"),
+				"Missing SSH authorized keys",
+				`The "ssh_authorized_keys" attribute is required within the "access" block.`,
+			)
+		}
+	}
+
+	// Anti-affinity label must be one of the instance labels.
+	if !config.Placement.IsNull() && !config.Placement.IsUnknown() && !config.Labels.IsUnknown() {
+		var placement PlacementModel
+		resp.Diagnostics.Append(config.Placement.As(ctx, &placement, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		label := placement.AntiAffinityLabel
+		_, ok := config.Labels.Elements()[label.ValueString()]
+		if !label.IsNull() && !label.IsUnknown() && !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("placement").AtName("anti_affinity_label"),
+				"Invalid anti-affinity label",
+				fmt.Sprintf("Anti-affinity label %q must be set in the instance %q attribute.", label.ValueString(), "labels"),
+			)
+		}
+	}
+
 	// Ensure empty container cannot be started.
-	if running && (config.Image.IsNull() || config.Image.ValueString() == "") && config.Type.ValueString() == "container" {
+	if running && sourceBackup == nil && (config.Image.IsNull() || config.Image.ValueString() == "") && config.Type.ValueString() == "container" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("image"),
 			fmt.Sprintf("Instance %q is a container and requires image", config.Name.ValueString()),
@@ -626,21 +1286,82 @@ func validateWaitFor(ctx context.Context, config InstanceModel, resp *resource.V
 			if waitFor.Delay.IsNull() {
 				resp.Diagnostics.AddError(
 					"Invalid Configuration",
-					`The "delay" attribute is required when wait_for type is "delay".`,
+					`The "delay" attribute is required when wait_for type is "delay".`,
+				)
+			} else if _, err := time.ParseDuration(waitFor.Delay.ValueString()); err != nil {
+				resp.Diagnostics.AddError(
+					"Invalid Configuration",
+					fmt.Sprintf("Invalid wait_for delay duration %q: %v.", waitFor.Delay.ValueString(), err),
+				)
+			}
+		}
+
+		// "delay" attribute is only valid for the "delay" type.
+		if !waitFor.IsDelay() && !waitFor.Delay.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "delay" attribute can only be set when wait_for type is "delay".`,
+			)
+		}
+
+		// "command" is required for and only valid for the "exec" type.
+		if waitFor.IsExec() && waitFor.Command.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "command" attribute is required when wait_for type is "exec".`,
+			)
+		} else if !waitFor.IsExec() && !waitFor.Command.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "command" attribute can only be set when wait_for type is "exec".`,
+			)
+		}
+
+		// "port" is required for and only valid for the "port" type.
+		if waitFor.IsPort() && waitFor.Port.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "port" attribute is required when wait_for type is "port".`,
+			)
+		} else if !waitFor.IsPort() && !waitFor.Port.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "port" attribute can only be set when wait_for type is "port".`,
+			)
+		}
+
+		// "path" is required for and only valid for the "file" type.
+		if waitFor.IsFile() {
+			if waitFor.Path.IsNull() {
+				resp.Diagnostics.AddError(
+					"Invalid Configuration",
+					`The "path" attribute is required when wait_for type is "file".`,
 				)
-			} else if _, err := time.ParseDuration(waitFor.Delay.ValueString()); err != nil {
+			} else if !waitFor.Path.IsUnknown() && !strings.HasPrefix(waitFor.Path.ValueString(), "/") {
 				resp.Diagnostics.AddError(
 					"Invalid Configuration",
-					fmt.Sprintf("Invalid wait_for delay duration %q: %v.", waitFor.Delay.ValueString(), err),
+					fmt.Sprintf("Invalid wait_for path %q: Path must be absolute.", waitFor.Path.ValueString()),
 				)
 			}
+		} else if !waitFor.Path.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "path" attribute can only be set when wait_for type is "file".`,
+			)
 		}
 
-		// "delay" attribute is only valid for the "delay" type.
-		if !waitFor.IsDelay() && !waitFor.Delay.IsNull() {
+		// "interval" and "timeout" are only valid for probes.
+		if !waitFor.IsProbe() && !waitFor.Interval.IsNull() {
 			resp.Diagnostics.AddError(
 				"Invalid Configuration",
-				`The "delay" attribute can only be set when wait_for type is "delay".`,
+				`The "interval" attribute can only be set when wait_for type is "exec", "port", or "file".`,
+			)
+		}
+
+		if !waitFor.IsProbe() && !waitFor.Timeout.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "timeout" attribute can only be set when wait_for type is "exec", "port", or "file".`,
 			)
 		}
 	}
@@ -696,6 +1417,23 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	// Choose the cluster member according to the placement policy.
+	if !plan.Placement.IsNull() {
+		plan.Placement, diags = choosePlacement(ctx, server, project, plan.Placement, plan.Labels)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+
+		var placement PlacementModel
+		resp.Diagnostics.Append(plan.Placement.As(ctx, &placement, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		server = server.UseTarget(placement.Member.ValueString())
+	}
+
 	var imageRemote string
 	var imageServer lxd.ImageServer
 
@@ -728,10 +1466,44 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 	config, diags := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diags...)
 
+	labels, diags := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	maps.Copy(config, common.LabelsToConfig(labels))
+
+	access, diags := ToAccessModel(ctx, plan.Access)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	// Provision the access user via cloud-init vendor data, unless
+	// vendor data is set by the user, either in the instance config or
+	// in any of the applied profiles. Otherwise, the generated vendor
+	// data would override it.
+	accessCloudInit := false
+	if access != nil && !common.HasVendorData(config) {
+		profileVendorData, err := profilesHaveVendorData(server, profiles)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to create instance %q", plan.Name.ValueString()), err.Error())
+			return
+		}
+
+		if !profileVendorData {
+			config[common.AccessVendorDataKey(server)], diags = accessVendorData(ctx, *access)
+			if diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			}
+
+			accessCloudInit = true
+		}
+	}
+
 	for _, device := range devices {
 		// Mark the device as managed by terraform to differentiate between
 		// devices added by terraform and devices added manually.
@@ -780,8 +1552,17 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 		}
 	}
 
+	// In case the backup source is set, restore the instance from the backup tarball.
 	// In case the image is set, create the instance from it, otherwise create it without rootfs. Similar to the --empty CLI flag on lxc.
-	if image != "" {
+	if !plan.SourceBackup.IsNull() {
+		var sourceBackup SourceBackupModel
+		resp.Diagnostics.Append(plan.SourceBackup.As(ctx, &sourceBackup, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		err = createInstanceFromBackup(ctx, server, instance, sourceBackup)
+	} else if image != "" {
 		var opCreateFromImage lxd.RemoteOperation
 		opCreateFromImage, err = server.CreateInstanceFromImage(imageServer, *imageInfo, instance)
 		if err == nil {
@@ -822,6 +1603,22 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 				return
 			}
 		}
+
+		// Provision the access user.
+		if access != nil {
+			method, diags := provisionAccess(ctx, server, instance.Name, *access, nil, accessCloudInit)
+			if diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			}
+
+			access.Method = types.StringValue(method)
+			plan.Access, diags = ToAccessObjectType(ctx, plan.Access, *access)
+			if diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			}
+		}
 	}
 
 	// Upload files.
@@ -832,12 +1629,16 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 			return
 		}
 
-		for _, f := range files {
-			err := common.InstanceFileUpload(server, instance.Name, f)
-			if err != nil {
-				resp.Diagnostics.AddError(fmt.Sprintf("Failed to upload file to instance %q", instance.Name), err.Error())
-				return
-			}
+		files, err := uploadInstanceFiles(server, instance.Name, files, nil)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to upload file to instance %q", instance.Name), err.Error())
+			return
+		}
+
+		plan.Files, diags = common.ToFileSetType(ctx, files)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
 		}
 	}
 
@@ -961,7 +1762,11 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 	userConfig, diags := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diags...)
 
+	labels, diags := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+
 	config := common.MergeConfig(instance.Config, userConfig, plan.ComputedKeys())
+	maps.Copy(config, common.LabelsToConfig(labels))
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -1051,17 +1856,28 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 		device[common.UserManagedBy] = common.DeviceManagedByTerraform
 	}
 
-	// Ensure that devices managed by the InstanceDeviceResource are not removed.
+	// Ensure that devices managed by the InstanceDeviceResource are not
+	// removed. Unmanaged devices are also preserved if they are not
+	// tracked in the state, and are marked as managed if they are
+	// being adopted.
+	policy := plan.UnmanagedDevices.ValueString()
+	trackUnmanaged := common.TrackUnmanagedDevices(policy)
 	for deviceName, device := range instance.Devices {
-		managedBy := device[common.UserManagedBy]
-		if managedBy != common.DeviceManagedByTerraform {
+		managed := common.IsManagedDevice(device)
+		if !managed && trackUnmanaged {
 			continue
 		}
 
 		_, alreadyAdded := devices[deviceName]
-		if !alreadyAdded {
-			devices[deviceName] = device
+		if alreadyAdded {
+			continue
 		}
+
+		if !managed && policy == common.UnmanagedDevicesAdopt {
+			device[common.UserManagedBy] = common.DeviceManagedByTerraform
+		}
+
+		devices[deviceName] = device
 	}
 
 	newInstance := api.InstancePut{
@@ -1128,6 +1944,52 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 		}
 	}
 
+	// Provision the access user if the access block has changed or
+	// the user has not been provisioned yet.
+	access, diags := ToAccessModel(ctx, plan.Access)
+	resp.Diagnostics.Append(diags...)
+
+	stateAccess, diags := ToAccessModel(ctx, state.Access)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Revoke access of the previously provisioned user if the user has
+	// changed or the access block has been removed.
+	if stateAccess != nil && !stateAccess.Method.IsNull() && (access == nil || !access.User.Equal(stateAccess.User)) {
+		diags := revokeAccess(ctx, server, instanceName, *stateAccess)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	if access != nil && (stateAccess == nil || stateAccess.Method.IsNull() || !access.equal(*stateAccess)) {
+		if instanceStopped {
+			// Provision the user once the instance is started.
+			access.Method = types.StringNull()
+		} else {
+			// Cloud-init provisions the user only on the first boot.
+			cloudInit := instanceStarted && stateAccess != nil && stateAccess.Method.IsNull() && instance.Config[common.AccessVendorDataKey(server)] != ""
+
+			method, diags := provisionAccess(ctx, server, instanceName, *access, stateAccess, cloudInit)
+			if diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			}
+
+			access.Method = types.StringValue(method)
+		}
+
+		plan.Access, diags = ToAccessObjectType(ctx, plan.Access, *access)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
 	oldFiles, diags := common.ToFileMap(ctx, state.Files)
 	resp.Diagnostics.Append(diags...)
 
@@ -1141,7 +2003,21 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 	// Remove files that are no longer present in newFiles.
 	for _, f := range oldFiles {
 		targetPath := f.TargetPath.ValueString()
-		err := common.InstanceFileDelete(server, instanceName, targetPath)
+
+		var err error
+		if f.IsDir() {
+			// Directories that are uploaded again are synchronized
+			// instead of being removed.
+			newFile, ok := newFiles[targetPath]
+			if ok && newFile.IsDir() {
+				continue
+			}
+
+			err = common.InstanceDirDelete(server, instanceName, targetPath)
+		} else {
+			err = common.InstanceFileDelete(server, instanceName, targetPath)
+		}
+
 		if err != nil {
 			resp.Diagnostics.AddError(fmt.Sprintf("Failed to delete file from instance %q", instanceName), err.Error())
 			return
@@ -1149,12 +2025,18 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	// Upload new files.
-	for _, f := range newFiles {
-		err := common.InstanceFileUpload(server, instanceName, f)
+	if len(newFiles) > 0 {
+		newFiles, err := uploadInstanceFiles(server, instanceName, newFiles, oldFiles)
 		if err != nil {
 			resp.Diagnostics.AddError(fmt.Sprintf("Failed to upload file to instance %q", instanceName), err.Error())
 			return
 		}
+
+		plan.Files, diags = common.ToFileSetType(ctx, newFiles)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
 	}
 
 	// Execute commands.
@@ -1227,6 +2109,38 @@ func (r InstanceResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	instanceName := state.Name.ValueString()
 
+	// Execute "on_destroy" commands while the instance is still running.
+	execs, diags := common.ToExecMap(ctx, state.Execs)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	destroyExecs := make(map[string]*common.ExecModel)
+	for k, e := range execs {
+		if e.IsDestroyTriggered() {
+			destroyExecs[k] = e
+		}
+	}
+
+	if len(destroyExecs) > 0 {
+		instanceState, _, err := server.GetInstanceState(instanceName)
+		if err != nil && !errors.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve state of instance %q", instanceName), err.Error())
+			return
+		}
+
+		if err == nil && isInstanceRunning(*instanceState) {
+			for _, k := range utils.SortMapKeys(destroyExecs) {
+				diags := destroyExecs[k].Execute(ctx, server, instanceName)
+				if diags.HasError() {
+					resp.Diagnostics.Append(diags...)
+					return
+				}
+			}
+		}
+	}
+
 	// Force stop the instance, because we are deleting it anyway.
 	isFound, diag := stopInstance(ctx, server, instanceName, true)
 	if diag != nil {
@@ -1314,148 +2228,293 @@ func (r InstanceResource) SyncState(ctx context.Context, tfState *tfsdk.State, s
 	m.IPv6 = types.StringNull()
 	m.MAC = types.StringNull()
 
-	accIface, ok := instance.ExpandedConfig["user.access_interface"]
-	if ok {
-		// If there is an user.access_interface set, extract IPv4, IPv6 and
-		// MAC addresses from that network interface.
-		net, ok := instanceState.Network[accIface]
-		if ok {
-			m.MAC = types.StringValue(net.Hwaddr)
-			ipv4, ipv6 := findGlobalIPAddresses(net)
+	ipv4, ipv6, mac := findAccessAddresses(*instance, *instanceState)
+	if mac != "" {
+		m.MAC = types.StringValue(mac)
+	}
 
-			if ipv4 != "" {
-				m.IPv4 = types.StringValue(ipv4)
-			}
+	if ipv4 != "" {
+		m.IPv4 = types.StringValue(ipv4)
+	}
 
-			if ipv6 != "" {
-				m.IPv6 = types.StringValue(ipv6)
-			}
-		}
-	} else {
-		// Search for the first interface (alphabetically sorted) that has
-		// global IPv4 or IPv6 address.
-		for _, iface := range utils.SortMapKeys(instanceState.Network) {
-			if iface == "lo" {
-				continue
-			}
+	if ipv6 != "" {
+		m.IPv6 = types.StringValue(ipv6)
+	}
 
-			net := instanceState.Network[iface]
-			ipv4, ipv6 := findGlobalIPAddresses(net)
-			if ipv4 != "" || ipv6 != "" {
-				m.MAC = types.StringValue(net.Hwaddr)
+	// Extract user defined config and merge it with current resource config.
+	// Labels are excluded from the config.
+	stateConfig := common.StripConfig(common.StripLabels(instance.Config), m.Config, m.ComputedKeys())
 
-				if ipv4 != "" {
-					m.IPv4 = types.StringValue(ipv4)
-				}
+	// Get devices configured using this instance resource (not device resource).
+	configuredDevices, diags := common.ToDeviceMap(ctx, m.Devices)
+	respDiags.Append(diags...)
+	if respDiags.HasError() {
+		return respDiags
+	}
 
-				if ipv6 != "" {
-					m.IPv6 = types.StringValue(ipv6)
-				}
+	// Devices to save as part of Instance Resource state. Devices managed
+	// by the instance device resource are excluded.
+	syncDevices := common.SyncDevices(instance.Devices, configuredDevices, m.UnmanagedDevices.ValueString())
 
-				break
-			}
+	// Convert config, profiles, and devices into schema type.
+	config, diags := common.ToConfigValueMapType(ctx, stateConfig, m.Config)
+	respDiags.Append(diags...)
+
+	labels, diags := common.ToLabelsMapType(ctx, instance.Config, m.Labels)
+	respDiags.Append(diags...)
+
+	profiles, diags := ToProfileListType(ctx, instance.Profiles)
+	respDiags.Append(diags...)
+
+	devices, diags := common.ToDeviceSetType(ctx, syncDevices)
+	respDiags.Append(diags...)
+
+	interfaces, diags := common.ToInterfaceMapType(ctx, instanceState.Network, instance.Config)
+	respDiags.Append(diags...)
+
+	expandedConfig, diags := types.MapValueFrom(ctx, types.StringType, getExpandedConfig(*instance))
+	respDiags.Append(diags...)
+
+	expandedDevices, diags := common.ToDeviceMapType(ctx, getExpandedDevices(*instance))
+	respDiags.Append(diags...)
+
+	if respDiags.HasError() {
+		return respDiags
+	}
+
+	files, diags := syncInstanceFiles(ctx, server, instanceName, m.Files)
+	respDiags.Append(diags...)
+	if respDiags.HasError() {
+		return respDiags
+	}
+
+	m.Name = types.StringValue(instance.Name)
+	m.Files = files
+	m.Type = types.StringValue(instance.Type)
+	m.Description = types.StringValue(instance.Description)
+	m.Ephemeral = types.BoolValue(instance.Ephemeral)
+	m.Status = types.StringValue(instance.Status)
+	m.Profiles = profiles
+	m.Devices = devices
+	m.Interfaces = interfaces
+	m.ExpandedConfig = expandedConfig
+	m.ExpandedDevices = expandedDevices
+	m.Config = config
+	m.Labels = labels
+
+	// Update "running" attribute based on the instance's current status.
+	// This way, terraform will detect the change if the current status
+	// does not match the expected one.
+	m.Running = types.BoolValue(instanceState.Status == api.Running.String())
+
+	m.Location = types.StringValue("")
+	if server.IsClustered() || instance.Location != "none" {
+		m.Location = types.StringValue(instance.Location)
+
+		// Check if the instance is located on the configured cluster
+		// member or within the configured cluster member group.
+		ok, err := checkInstanceLocation(server, instance.Location, m.Target.ValueString())
+		if err != nil {
+			respDiags.AddError("Failed to check if instance is located on correct cluster member", err.Error())
+			return respDiags
+		}
+
+		if !ok {
+			// Trigger plan mismatch by setting target to an
+			// actual instance location.
+			m.Target = m.Location
 		}
 	}
 
-	// Extract user defined config and merge it with current resource config.
-	stateConfig := common.StripConfig(instance.Config, m.Config, m.ComputedKeys())
+	// Detect drift of the SSH authorized keys of the access user.
+	if isInstanceRunning(*instanceState) {
+		m.Access, diags = syncAccess(ctx, server, instanceName, m.Access)
+		respDiags.Append(diags...)
+		if respDiags.HasError() {
+			return respDiags
+		}
+	}
 
-	// Get devices configured using this instance resource (not device resource).
-	configuredDevices, diags := common.ToDeviceMap(ctx, m.Devices)
+	// Access user is provisioned only in a running instance.
+	access, diags := ToAccessModel(ctx, m.Access)
 	respDiags.Append(diags...)
 	if respDiags.HasError() {
 		return respDiags
 	}
 
-	// Devices to save as part of Instance Resource state.
-	var syncDevices = make(map[string]map[string]string)
+	if access != nil && access.Method.IsUnknown() {
+		access.Method = types.StringNull()
+		m.Access, diags = ToAccessObjectType(ctx, m.Access, *access)
+		respDiags.Append(diags...)
+		if respDiags.HasError() {
+			return respDiags
+		}
+	}
 
-	for deviceName, device := range instance.Devices {
-		managedBy := device[common.UserManagedBy]
+	// Placement is only applied on creation. If the placement block is
+	// added to an existing instance, record its current location.
+	if !m.Placement.IsNull() && !m.Placement.IsUnknown() {
+		var placement PlacementModel
+		respDiags.Append(m.Placement.As(ctx, &placement, basetypes.ObjectAsOptions{})...)
+		if respDiags.HasError() {
+			return respDiags
+		}
+
+		if placement.Member.IsUnknown() || placement.Member.IsNull() {
+			placement.Member = types.StringValue(instance.Location)
+			placement.Reason = types.StringValue("Instance was created before the placement policy was set")
 
-		// Add non-managed devices for deletion by terraform plan.
-		if managedBy != common.DeviceManagedByTerraform {
-			syncDevices[deviceName] = device
+			m.Placement, diags = types.ObjectValueFrom(ctx, m.Placement.AttributeTypes(ctx), placement)
+			respDiags.Append(diags...)
+			if respDiags.HasError() {
+				return respDiags
+			}
 		}
 	}
 
-	for deviceName := range configuredDevices {
-		// Keep the devices that were both configured for this resource
-		// and exist in actual LXD state.
-		if device, ok := instance.Devices[deviceName]; ok {
-			// Delete "user.managed-by" key from internal state to avoid config mismatch.
-			delete(device, common.UserManagedBy)
+	// Ensure default values are set for provider specific attributes
+	// to prevent plan diff on import.
+	if m.AllowRestart.IsNull() {
+		m.AllowRestart = types.BoolValue(false)
+	}
+
+	return tfState.Set(ctx, &m)
+}
+
+// uploadInstanceFiles uploads files to the instance. Templated files are
+// rendered with the current instance facts first, and the checksum of the
+// rendered content is recorded. Directories that were uploaded previously
+// are synchronized with the previous upload. Returned files retain the
+// original content.
+func uploadInstanceFiles(server lxd.InstanceServer, instanceName string, files map[string]common.InstanceFileModel, previous map[string]common.InstanceFileModel) (map[string]common.InstanceFileModel, error) {
+	rendered, err := renderInstanceFiles(server, instanceName, files)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, f := range rendered {
+		prevFile, ok := previous[k]
+		if ok && f.IsDir() && prevFile.IsDir() {
+			err = common.InstanceDirUpload(server, instanceName, f, &prevFile)
+		} else {
+			err = common.InstanceFileUpload(server, instanceName, f)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		file := files[k]
+		if file.IsTemplate() {
+			hash, err := common.InstanceFileHash(f)
+			if err != nil {
+				return nil, err
+			}
 
-			syncDevices[deviceName] = device
+			file.SHA256 = types.StringValue(hash)
+			files[k] = file
 		}
 	}
 
-	// Convert config, profiles, and devices into schema type.
-	config, diags := common.ToConfigMapType(ctx, stateConfig, m.Config)
-	respDiags.Append(diags...)
+	return files, nil
+}
 
-	profiles, diags := ToProfileListType(ctx, instance.Profiles)
-	respDiags.Append(diags...)
+// syncInstanceFiles compares files uploaded using the file blocks with
+// the files within the instance. Files that no longer exist are removed,
+// while the owner, mode, and checksum of the content are updated to
+// reflect the files within the instance, which produces a diff if the
+// files have been modified.
+func syncInstanceFiles(ctx context.Context, server lxd.InstanceServer, instanceName string, fileSet types.Set) (types.Set, diag.Diagnostics) {
+	if fileSet.IsNull() || fileSet.IsUnknown() {
+		return fileSet, nil
+	}
+
+	files, diags := common.ToFileMap(ctx, fileSet)
+	if diags.HasError() {
+		return fileSet, diags
+	}
+
+	for k, f := range files {
+		info, err := common.GetInstanceFileInfo(server, instanceName, f.TargetPath.ValueString(), f.IsContentChecked())
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				delete(files, k)
+				continue
+			}
 
-	devices, diags := common.ToDeviceSetType(ctx, syncDevices)
-	respDiags.Append(diags...)
+			// Files cannot always be retrieved (e.g. from a stopped
+			// virtual machine), in which case the state is kept as is.
+			info = nil
+		}
 
-	interfaces, diags := common.ToInterfaceMapType(ctx, instanceState.Network, instance.Config)
-	respDiags.Append(diags...)
+		if info != nil {
+			// Owner defaults to root, therefore keep unset values
+			// unset unless they differ.
+			if !f.UserID.IsNull() || info.UID != 0 {
+				f.UserID = types.Int64Value(info.UID)
+			}
 
-	if respDiags.HasError() {
-		return respDiags
-	}
+			if !f.GroupID.IsNull() || info.GID != 0 {
+				f.GroupID = types.Int64Value(info.GID)
+			}
 
-	m.Name = types.StringValue(instance.Name)
-	m.Type = types.StringValue(instance.Type)
-	m.Description = types.StringValue(instance.Description)
-	m.Ephemeral = types.BoolValue(instance.Ephemeral)
-	m.Status = types.StringValue(instance.Status)
-	m.Profiles = profiles
-	m.Devices = devices
-	m.Interfaces = interfaces
-	m.Config = config
+			// Compare parsed modes to tolerate different notations
+			// of the same mode (e.g. "644" and "0644"). Permissions
+			// of uploaded directories are preserved from the source
+			// directory, therefore their mode is not tracked.
+			mode, err := strconv.ParseInt(f.Mode.ValueString(), 8, 32)
+			if !f.IsDir() && (err != nil || int(mode) != info.Mode) {
+				f.Mode = types.StringValue(fmt.Sprintf("%04o", info.Mode))
+			}
 
-	// Update "running" attribute based on the instance's current status.
-	// This way, terraform will detect the change if the current status
-	// does not match the expected one.
-	m.Running = types.BoolValue(instanceState.Status == api.Running.String())
+			if f.IsContentChecked() {
+				f.SHA256 = types.StringValue(info.SHA256)
+			}
+		}
 
-	m.Location = types.StringValue("")
-	if server.IsClustered() || instance.Location != "none" {
-		m.Location = types.StringValue(instance.Location)
+		// Computed values must be known after apply.
+		if f.Mode.IsUnknown() {
+			f.Mode = types.StringNull()
+		}
 
-		// Check if the instance is located on the configured cluster
-		// member or within the configured cluster member group.
-		ok, err := checkInstanceLocation(server, instance.Location, m.Target.ValueString())
-		if err != nil {
-			respDiags.AddError("Failed to check if instance is located on correct cluster member", err.Error())
-			return respDiags
+		if f.SHA256.IsUnknown() {
+			hash, err := common.InstanceFileHash(f)
+			if err != nil {
+				f.SHA256 = types.StringNull()
+			} else {
+				f.SHA256 = types.StringValue(hash)
+			}
 		}
 
-		if !ok {
-			// Trigger plan mismatch by setting target to an
-			// actual instance location.
-			m.Target = m.Location
+		if f.Manifest.IsUnknown() {
+			f.Manifest = types.MapNull(types.StringType)
+			if f.IsDir() {
+				manifest, err := common.InstanceDirManifest(f)
+				if err == nil {
+					f.Manifest = manifest
+				}
+			}
 		}
-	}
 
-	// Ensure default values are set for provider specific attributes
-	// to prevent plan diff on import.
-	if m.AllowRestart.IsNull() {
-		m.AllowRestart = types.BoolValue(false)
+		files[k] = f
 	}
 
-	return tfState.Set(ctx, &m)
+	return common.ToFileSetType(ctx, files)
 }
 
 // ComputedKeys returns list of computed config keys.
 func (m InstanceModel) ComputedKeys() []string {
-	return []string{
+	keys := []string{
 		"image.",
 		"volatile.",
 	}
+
+	// Vendor data is generated from the access block.
+	if !m.Access.IsNull() {
+		keys = append(keys, common.VendorDataKey, common.LegacyVendorDataKey)
+	}
+
+	return keys
 }
 
 // ToProfileList converts profiles of type types.List into []string.
@@ -1633,12 +2692,26 @@ func waitFor(ctx context.Context, server lxd.InstanceServer, instanceName string
 			d = waitForInstanceNetwork(ctx, server, instanceName, waitForType, nic)
 		case "ready":
 			d = waitForInstanceToBeReady(ctx, server, instanceName)
+		case "exec", "port", "file":
+			d = waitForInstanceProbe(ctx, server, instanceName, waitForModel)
 		default:
 			d.AddError(fmt.Sprintf("Invalid value for wait_for: %q", waitForType), "")
 		}
 
 		diags.Append(d...)
 		if diags.HasError() {
+			// Include the tail of the console log to help diagnose
+			// why the instance did not reach the expected state.
+			if waitForType != "delay" {
+				consoleLog, err := getInstanceConsoleLog(server, instanceName)
+				if err == nil && strings.TrimSpace(consoleLog) != "" {
+					diags.AddWarning(
+						fmt.Sprintf("Console log of instance %q (last %d lines)", instanceName, consoleLogTailLines),
+						tailLines(consoleLog, consoleLogTailLines),
+					)
+				}
+			}
+
 			return diags
 		}
 	}
@@ -1761,6 +2834,166 @@ func waitForInstanceToBeReady(ctx context.Context, server lxd.InstanceServer, in
 	return nil
 }
 
+// waitForInstanceProbe periodically probes the instance until the exec,
+// port, or file condition of the given wait_for configuration is met, or
+// until the configured timeout is reached.
+func waitForInstanceProbe(ctx context.Context, server lxd.InstanceServer, instanceName string, waitForModel WaitForModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	interval := 5 * time.Second
+	if waitForModel.Interval.ValueString() != "" {
+		d, err := time.ParseDuration(waitForModel.Interval.ValueString())
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to parse wait_for interval for instance %q", instanceName), err.Error())
+			return diags
+		}
+
+		interval = d
+	}
+
+	timeout := 3 * time.Minute
+	if waitForModel.Timeout.ValueString() != "" {
+		d, err := time.ParseDuration(waitForModel.Timeout.ValueString())
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to parse wait_for timeout for instance %q", instanceName), err.Error())
+			return diags
+		}
+
+		timeout = d
+	}
+
+	var probe func() (bool, error)
+	var condition string
+
+	switch {
+	case waitForModel.IsExec():
+		command := make([]string, 0, len(waitForModel.Command.Elements()))
+		diags.Append(waitForModel.Command.ElementsAs(ctx, &command, false)...)
+		if diags.HasError() {
+			return diags
+		}
+
+		condition = fmt.Sprintf("command %q to succeed", strings.Join(command, " "))
+		probe = func() (bool, error) {
+			exitCode, _, err := probeInstanceExec(ctx, server, instanceName, command)
+			return exitCode == 0, err
+		}
+	case waitForModel.IsPort():
+		port := waitForModel.Port.ValueInt64()
+		condition = fmt.Sprintf("TCP port %d to be listening", port)
+		probe = func() (bool, error) {
+			// Read the kernel's TCP socket tables from within the instance,
+			// which does not require any networking tools to be installed.
+			_, stdout, err := probeInstanceExec(ctx, server, instanceName, []string{"cat", "/proc/net/tcp", "/proc/net/tcp6"})
+			return isTCPPortListening(stdout, port), err
+		}
+	case waitForModel.IsFile():
+		path := waitForModel.Path.ValueString()
+		condition = fmt.Sprintf("path %q to exist", path)
+		probe = func() (bool, error) {
+			content, _, err := server.GetInstanceFile(instanceName, path)
+			if err != nil {
+				return false, err
+			}
+
+			if content != nil {
+				_ = content.Close()
+			}
+
+			return true, nil
+		}
+	default:
+		diags.AddError(fmt.Sprintf("Invalid value for wait_for probe: %q", waitForModel.Type.ValueString()), "")
+		return diags
+	}
+
+	// Errors returned by the probe are not fatal, as the instance may not
+	// be ready to serve exec or file requests yet (e.g. VM agent is not
+	// running). Keep the last error to provide more context on timeout.
+	var lastErr error
+	check := func() (any, string, error) {
+		ok, err := probe()
+		if err != nil {
+			lastErr = err
+		}
+
+		if ok {
+			return ok, "OK", nil
+		}
+
+		return ok, "Waiting", nil
+	}
+
+	_, err := waitForStateWithInterval(ctx, check, interval, timeout, "OK")
+	if err != nil {
+		detail := err.Error()
+		if lastErr != nil {
+			detail = fmt.Sprintf("%v (last probe error: %v)", err, lastErr)
+		}
+
+		diags.AddError(fmt.Sprintf("Failed to wait for %s in instance %q", condition, instanceName), detail)
+		return diags
+	}
+
+	return nil
+}
+
+// probeInstanceExec executes the given command within the instance and
+// returns its exit code and standard output. Exit code -1 indicates the
+// command could not be executed.
+func probeInstanceExec(ctx context.Context, server lxd.InstanceServer, instanceName string, command []string) (int64, string, error) {
+	cmd, diags := types.ListValueFrom(ctx, types.StringType, command)
+	if diags.HasError() {
+		return -1, "", errors.FromDiagnostics(diags)
+	}
+
+	exec := common.ExecModel{
+		Command:      cmd,
+		Environment:  types.MapValueMust(types.StringType, map[string]attr.Value{}),
+		Enabled:      types.BoolValue(true),
+		RecordOutput: types.BoolValue(true),
+		FailOnError:  types.BoolValue(false),
+	}
+
+	diags = exec.Execute(ctx, server, instanceName)
+	if diags.HasError() {
+		return -1, "", errors.FromDiagnostics(diags)
+	}
+
+	exitCode := exec.ExitCode.ValueInt64()
+	if exitCode == -1 {
+		// Command was not executed, stderr contains the error.
+		return exitCode, "", fmt.Errorf("%s", exec.Error.ValueString())
+	}
+
+	return exitCode, exec.Output.ValueString(), nil
+}
+
+// isTCPPortListening parses the content of /proc/net/tcp and /proc/net/tcp6
+// and reports whether any socket is listening on the given local port.
+func isTCPPortListening(procNetTCP string, port int64) bool {
+	for line := range strings.SplitSeq(procNetTCP, "\n") {
+		// Format: "sl local_address rem_address st ...", where local
+		// address is "<hex-ip>:<hex-port>" and state 0A is LISTEN.
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[3] != "0A" {
+			continue
+		}
+
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+
+		p, err := strconv.ParseInt(hexPort, 16, 64)
+		if err == nil && p == port {
+			return true
+		}
+	}
+
+	return false
+}
+
 // waitForState waits until the provided function reports one of the target
 // states. It returns either the resulting state or an error.
 func waitForState(ctx context.Context, refreshFunc retry.StateRefreshFunc, targets ...string) (any, error) {
@@ -1775,6 +3008,20 @@ func waitForState(ctx context.Context, refreshFunc retry.StateRefreshFunc, targe
 	return stateRefreshConf.WaitForStateContext(ctx)
 }
 
+// waitForStateWithInterval waits until the provided function reports one of
+// the target states, refreshing the state at a fixed interval. It returns
+// either the resulting state or an error once the timeout is reached.
+func waitForStateWithInterval(ctx context.Context, refreshFunc retry.StateRefreshFunc, interval time.Duration, timeout time.Duration, targets ...string) (any, error) {
+	stateRefreshConf := &retry.StateChangeConf{
+		Refresh:      refreshFunc,
+		Target:       targets,
+		Timeout:      timeout,
+		PollInterval: interval,
+	}
+
+	return stateRefreshConf.WaitForStateContext(ctx)
+}
+
 // isInstanceOperational determines if an instance is fully operational based
 // on its state. It returns true if the instance is running and the reported
 // process count is positive. Checking for a positive process count is essential
@@ -1820,6 +3067,190 @@ func findGlobalIPAddresses(network api.InstanceStateNetwork) (ipv4 string, ipv6
 	return ipv4, ipv6
 }
 
+// findAccessAddresses returns the IPv4, IPv6, and MAC addresses used to
+// access the instance. If "user.access_interface" is set, addresses are
+// extracted from that network interface. Otherwise, addresses of the first
+// interface (alphabetically sorted) with a global address are returned.
+func findAccessAddresses(instance api.Instance, instanceState api.InstanceState) (ipv4 string, ipv6 string, mac string) {
+	accIface, ok := instance.ExpandedConfig["user.access_interface"]
+	if ok {
+		net, ok := instanceState.Network[accIface]
+		if !ok {
+			return "", "", ""
+		}
+
+		ipv4, ipv6 = findGlobalIPAddresses(net)
+		return ipv4, ipv6, net.Hwaddr
+	}
+
+	for _, iface := range utils.SortMapKeys(instanceState.Network) {
+		if iface == "lo" {
+			continue
+		}
+
+		net := instanceState.Network[iface]
+		ipv4, ipv6 = findGlobalIPAddresses(net)
+		if ipv4 != "" || ipv6 != "" {
+			return ipv4, ipv6, net.Hwaddr
+		}
+	}
+
+	return "", "", ""
+}
+
+// getExpandedConfig returns the instance configuration with profiles
+// applied. Volatile keys are excluded, as they are internal to LXD and
+// change frequently.
+func getExpandedConfig(instance api.Instance) map[string]string {
+	config := make(map[string]string, len(instance.ExpandedConfig))
+	for k, v := range instance.ExpandedConfig {
+		if !strings.HasPrefix(k, "volatile.") {
+			config[k] = v
+		}
+	}
+
+	return config
+}
+
+// getExpandedDevices returns the instance devices with profiles applied.
+// The key used to mark devices managed by Terraform is excluded.
+func getExpandedDevices(instance api.Instance) map[string]map[string]string {
+	devices := make(map[string]map[string]string, len(instance.ExpandedDevices))
+	for name, device := range instance.ExpandedDevices {
+		props := make(map[string]string, len(device))
+		for k, v := range device {
+			if k != common.UserManagedBy {
+				props[k] = v
+			}
+		}
+
+		devices[name] = props
+	}
+
+	return devices
+}
+
+// restoredInstanceName returns the name of the instance restored by the
+// backup import operation, which is recorded in the operation resources.
+// An empty string is returned if the name cannot be determined.
+func restoredInstanceName(op api.Operation) string {
+	for _, resource := range op.Resources["instances"] {
+		u, err := url.Parse(resource)
+		if err != nil {
+			continue
+		}
+
+		name := u.Path[strings.LastIndex(u.Path, "/")+1:]
+		if name != "" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// createInstanceFromBackup restores the instance from a local backup tarball
+// under the configured name and then applies the configuration, profiles,
+// and devices of the instance request, as the restored instance retains the
+// configuration recorded in the backup. Volatile keys of the restored
+// instance are preserved. If the restored instance cannot be configured,
+// it is removed, as it is not recorded in the Terraform state.
+func createInstanceFromBackup(ctx context.Context, server lxd.InstanceServer, instance api.InstancesPost, source SourceBackupModel) (err error) {
+	backupPath, err := homedir.Expand(source.Path.ValueString())
+	if err != nil {
+		return fmt.Errorf("Unable to determine backup file path: %v", err)
+	}
+
+	backupFile, err := os.Open(backupPath)
+	if err != nil {
+		return fmt.Errorf("Unable to read backup file: %v", err)
+	}
+
+	defer func() { _ = backupFile.Close() }()
+
+	args := lxd.InstanceBackupArgs{
+		BackupFile: backupFile,
+		PoolName:   source.Pool.ValueString(),
+	}
+
+	// Restore the instance under its configured name by default.
+	// Otherwise, the name recorded in the backup is used.
+	if source.NameOverride.IsNull() || source.NameOverride.ValueBool() {
+		args.Name = instance.Name
+	}
+
+	op, err := server.CreateInstanceFromBackup(args)
+	if err == nil {
+		err = op.WaitContext(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to restore instance from backup %q: %v", source.Path.ValueString(), err)
+	}
+
+	restoredName := args.Name
+	if restoredName == "" {
+		restoredName = restoredInstanceName(op.Get())
+		if restoredName == "" {
+			return fmt.Errorf("Failed to determine the name of the instance restored from backup %q", source.Path.ValueString())
+		}
+	}
+
+	// From here on, the restored instance exists on the server. Remove
+	// it on error. The removal is not bound to the context, as the error
+	// may be caused by the context timing out.
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		opDelete, errDelete := server.DeleteInstance(restoredName)
+		if errDelete == nil {
+			errDelete = opDelete.Wait()
+		}
+
+		if errDelete != nil && !errors.IsNotFoundError(errDelete) {
+			err = fmt.Errorf("%w (failed to remove restored instance %q: %v)", err, restoredName, errDelete)
+		}
+	}()
+
+	if restoredName != instance.Name {
+		return fmt.Errorf("Backup %q contains instance %q, but instance name %q is configured. Either set %q to true or use the instance name recorded in the backup.", source.Path.ValueString(), restoredName, instance.Name, "name_override")
+	}
+
+	restored, etag, err := server.GetInstance(instance.Name)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve restored instance %q: %v", instance.Name, err)
+	}
+
+	if restored.Type != string(instance.Type) {
+		return fmt.Errorf("Backup %q contains an instance of type %q, but type %q is configured", source.Path.ValueString(), restored.Type, instance.Type)
+	}
+
+	config := common.MergeConfig(restored.Config, instance.Config, []string{"image.", "volatile."})
+
+	newInstance := api.InstancePut{
+		Description:  instance.Description,
+		Ephemeral:    instance.Ephemeral,
+		Architecture: restored.Architecture,
+		Stateful:     restored.Stateful,
+		Config:       config,
+		Profiles:     instance.Profiles,
+		Devices:      instance.Devices,
+	}
+
+	opUpdate, err := server.UpdateInstance(instance.Name, newInstance, etag)
+	if err == nil {
+		err = opUpdate.WaitContext(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to apply configuration to restored instance %q: %v", instance.Name, err)
+	}
+
+	return nil
+}
+
 // checkInstanceLocation checks whether the instance is located on the
 // desired cluster member or within the desired cluster member group.
 func checkInstanceLocation(server lxd.InstanceServer, location string, target string) (bool, error) {
@@ -1853,6 +3284,81 @@ func checkInstanceLocation(server lxd.InstanceServer, location string, target st
 	return false, nil
 }
 
+// choosePlacement chooses the cluster member of a new instance according
+// to the given placement policy and returns the placement with the
+// decision recorded. Instances of all projects are taken into account,
+// because they all contribute to the load of the cluster members.
+func choosePlacement(ctx context.Context, server lxd.InstanceServer, project string, placementObj types.Object, labelMap types.Map) (types.Object, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var placement PlacementModel
+
+	diags.Append(placementObj.As(ctx, &placement, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return placementObj, diags
+	}
+
+	if !server.IsClustered() {
+		diags.AddAttributeError(path.Root("placement"), "Invalid placement", "Placement can only be used with a clustered LXD server.")
+		return placementObj, diags
+	}
+
+	policy := common.PlacementPolicy{
+		AntiAffinityLabel: placement.AntiAffinityLabel.ValueString(),
+	}
+
+	for _, set := range []struct {
+		value types.Set
+		dest  *[]string
+	}{
+		{placement.SpreadAcross, &policy.SpreadAcross},
+		{placement.PreferMembers, &policy.PreferMembers},
+		{placement.AvoidMembers, &policy.AvoidMembers},
+	} {
+		if !set.value.IsNull() {
+			diags.Append(set.value.ElementsAs(ctx, set.dest, false)...)
+		}
+	}
+
+	labels, d := common.ToConfigMap(ctx, labelMap)
+	diags.Append(d...)
+	if diags.HasError() {
+		return placementObj, diags
+	}
+
+	policy.AntiAffinityValue = labels[policy.AntiAffinityLabel]
+
+	members, err := server.GetClusterMembers()
+	if err != nil {
+		diags.AddError("Failed to retrieve cluster members", err.Error())
+		return placementObj, diags
+	}
+
+	// Fall back to the instances of the project if the instances of all
+	// projects cannot be retrieved (e.g. on older LXD servers).
+	scope := ""
+	instances, err := server.GetInstancesAllProjects(api.InstanceTypeAny)
+	if err != nil {
+		instances, err = server.GetInstances(api.InstanceTypeAny)
+		if err != nil {
+			diags.AddError("Failed to retrieve instances", err.Error())
+			return placementObj, diags
+		}
+
+		scope = fmt.Sprintf(" (only instances of project %q were considered)", project)
+	}
+
+	decision, err := common.ChoosePlacement(members, instances, policy)
+	if err != nil {
+		diags.AddAttributeError(path.Root("placement"), "Failed to choose cluster member", err.Error()+scope)
+		return placementObj, diags
+	}
+
+	placement.Member = types.StringValue(decision.Member)
+	placement.Reason = types.StringValue(decision.Reason + scope)
+
+	return types.ObjectValueFrom(ctx, placementObj.AttributeTypes(ctx), placement)
+}
+
 // ToWaitForList converts wait_for from types.Set into []WaitForModel.
 func ToWaitForList(ctx context.Context, waitForSet types.Set) ([]WaitForModel, diag.Diagnostics) {
 	if waitForSet.IsNull() || waitForSet.IsUnknown() {
@@ -0,0 +1,456 @@
+package instance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+type InstanceBackupModel struct {
+	Name                 types.String `tfsdk:"name"`
+	Instance             types.String `tfsdk:"instance"`
+	InstanceOnly         types.Bool   `tfsdk:"instance_only"`
+	OptimizedStorage     types.Bool   `tfsdk:"optimized_storage"`
+	CompressionAlgorithm types.String `tfsdk:"compression_algorithm"`
+	ExpiresAt            types.String `tfsdk:"expires_at"`
+	OutputPath           types.String `tfsdk:"output_path"`
+	Triggers             types.Map    `tfsdk:"triggers"`
+	Project              types.String `tfsdk:"project"`
+	Remote               types.String `tfsdk:"remote"`
+
+	// Computed.
+	CreatedAt types.Int64  `tfsdk:"created_at"`
+	SHA256    types.String `tfsdk:"sha256"`
+	Size      types.Int64  `tfsdk:"size"`
+
+	// Timeouts.
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// InstanceBackupResource represent LXD instance backup resource.
+type InstanceBackupResource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+// NewInstanceBackupResource returns a new instance backup resource.
+func NewInstanceBackupResource() resource.Resource {
+	return &InstanceBackupResource{}
+}
+
+func (r InstanceBackupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instance_backup", req.ProviderTypeName)
+}
+
+func (r InstanceBackupResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"instance": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"instance_only": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether to exclude instance snapshots from the backup",
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"optimized_storage": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether to use the storage driver optimized format",
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"compression_algorithm": schema.StringAttribute{
+				Optional:    true,
+				Description: "Compression algorithm of the backup tarball",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"expires_at": schema.StringAttribute{
+				Optional:    true,
+				Description: "Time (RFC 3339) when the backup is removed from the server",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					rfc3339Validator{},
+				},
+			},
+
+			"output_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Local path to which the backup tarball is downloaded",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"triggers": schema.MapAttribute{
+				Optional:    true,
+				Description: "Arbitrary values that, when changed, trigger a new backup",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(provider_config.DefaultProject),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// Computed.
+
+			"created_at": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 checksum of the downloaded backup tarball",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Size of the downloaded backup tarball in bytes",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+
+			// Custom timeouts
+			"timeouts": timeouts.AttributesAll(ctx),
+		},
+	}
+}
+
+func (r *InstanceBackupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	r.provider = provider
+}
+
+func (r InstanceBackupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan InstanceBackupModel
+
+	// Fetch resource model from Terraform plan.
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set creation timeout.
+	timeout, diags := plan.Timeouts.Create(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := plan.Instance.ValueString()
+	backupName := plan.Name.ValueString()
+
+	backupReq := api.InstanceBackupsPost{
+		Name:                 backupName,
+		InstanceOnly:         plan.InstanceOnly.ValueBool(),
+		OptimizedStorage:     plan.OptimizedStorage.ValueBool(),
+		CompressionAlgorithm: plan.CompressionAlgorithm.ValueString(),
+	}
+
+	if plan.ExpiresAt.ValueString() != "" {
+		// Format is already validated.
+		backupReq.ExpiresAt, _ = time.Parse(time.RFC3339, plan.ExpiresAt.ValueString())
+	}
+
+	op, err := server.CreateInstanceBackup(instanceName, backupReq)
+	if err == nil {
+		err = op.WaitContext(ctx)
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to create backup %q for instance %q", backupName, instanceName), err.Error())
+		return
+	}
+
+	backup, _, err := server.GetInstanceBackup(instanceName, backupName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve backup %q for instance %q", backupName, instanceName), err.Error())
+		return
+	}
+
+	plan.CreatedAt = types.Int64Value(backup.CreatedAt.Unix())
+	plan.SHA256 = types.StringNull()
+	plan.Size = types.Int64Null()
+
+	// Download the backup tarball.
+	outputPath := plan.OutputPath.ValueString()
+	if outputPath != "" {
+		checksum, size, err := downloadInstanceBackup(server, instanceName, backupName, outputPath)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to download backup %q for instance %q", backupName, instanceName), err.Error())
+
+			// Store the state, so that the server-side backup
+			// is removed when the resource is destroyed.
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+			return
+		}
+
+		plan.SHA256 = types.StringValue(checksum)
+		plan.Size = types.Int64Value(size)
+	}
+
+	// Update Terraform state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r InstanceBackupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state InstanceBackupModel
+
+	// Fetch resource model from Terraform state.
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set read timeout.
+	timeout, diags := state.Timeouts.Read(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Instance.ValueString()
+	backupName := state.Name.ValueString()
+
+	// The server-side backup may be removed once it expires, while the
+	// downloaded tarball remains. In such case, leave the state as is to
+	// prevent a new backup from being created on each apply. Use triggers
+	// to create new backups.
+	backup, _, err := server.GetInstanceBackup(instanceName, backupName)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return
+		}
+
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve backup %q for instance %q", backupName, instanceName), err.Error())
+		return
+	}
+
+	state.InstanceOnly = types.BoolValue(backup.InstanceOnly)
+	state.OptimizedStorage = types.BoolValue(backup.OptimizedStorage)
+	state.CreatedAt = types.Int64Value(backup.CreatedAt.Unix())
+
+	// Update Terraform state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update only stores the planned timeouts, as all other attributes
+// require replacement.
+func (r InstanceBackupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan InstanceBackupModel
+
+	// Fetch resource model from Terraform plan.
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update Terraform state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r InstanceBackupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state InstanceBackupModel
+
+	// Fetch resource model from Terraform state.
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set deletion timeout.
+	timeout, diags := state.Timeouts.Delete(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Only the server-side backup is removed. The downloaded
+	// tarball is left intact.
+	instanceName := state.Instance.ValueString()
+	backupName := state.Name.ValueString()
+	op, err := server.DeleteInstanceBackup(instanceName, backupName)
+	if err == nil {
+		err = op.WaitContext(ctx)
+	}
+
+	if err != nil && !errors.IsNotFoundError(err) {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove backup %q for instance %q", backupName, instanceName), err.Error())
+		return
+	}
+}
+
+// downloadInstanceBackup downloads the backup tarball to the output path
+// and returns its SHA-256 checksum and size. The tarball is first written
+// to a temporary file, so that an existing file at the output path is not
+// corrupted if the download fails.
+func downloadInstanceBackup(server lxd.InstanceServer, instanceName string, backupName string, outputPath string) (string, int64, error) {
+	outputPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return "", 0, err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(outputPath), filepath.Base(outputPath)+".*.tmp")
+	if err != nil {
+		return "", 0, fmt.Errorf("Failed to create temporary file: %v", err)
+	}
+
+	defer func() {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	_, err = server.GetInstanceBackupFile(instanceName, backupName, &lxd.BackupFileRequest{
+		BackupFile: tmpFile,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	_, err = tmpFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", 0, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, tmpFile)
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = os.Rename(tmpFile.Name(), outputPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("Failed to write file %q: %v", outputPath, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
@@ -0,0 +1,158 @@
+package instance_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceBackup_basic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	backupName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceBackup_basic(instanceName, backupName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_backup.backup1", "name", backupName),
+					resource.TestCheckResourceAttr("lxd_instance_backup.backup1", "instance", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance_backup.backup1", "instance_only", "true"),
+					resource.TestCheckResourceAttr("lxd_instance_backup.backup1", "optimized_storage", "false"),
+					resource.TestCheckResourceAttrSet("lxd_instance_backup.backup1", "created_at"),
+					resource.TestCheckNoResourceAttr("lxd_instance_backup.backup1", "sha256"),
+					resource.TestCheckNoResourceAttr("lxd_instance_backup.backup1", "size"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceBackup_outputPath(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	backupName := acctest.GenerateName(2, "-")
+	outputPath := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceBackup_outputPath(instanceName, backupName, outputPath, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_backup.backup1", "output_path", outputPath),
+					resource.TestCheckResourceAttr("lxd_instance_backup.backup1", "compression_algorithm", "gzip"),
+					resource.TestCheckResourceAttr("lxd_instance_backup.backup1", "expires_at", "2099-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttrSet("lxd_instance_backup.backup1", "sha256"),
+					resource.TestCheckResourceAttrSet("lxd_instance_backup.backup1", "size"),
+					testAccCheckBackupFileExists(outputPath),
+				),
+			},
+			{
+				// Changing triggers creates a new backup.
+				Config: acctest.Provider() + testAccInstanceBackup_outputPath(instanceName, backupName, outputPath, "2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_instance_backup.backup1", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_backup.backup1", "triggers.generation", "2"),
+					testAccCheckBackupFileExists(outputPath),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceBackup_invalidExpiresAt(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	backupName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstanceBackup_expiresAt(instanceName, backupName, "tomorrow"),
+				ExpectError: regexp.MustCompile(`Invalid timestamp`),
+			},
+		},
+	})
+}
+
+func testAccCheckBackupFileExists(outputPath string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		info, err := os.Stat(outputPath)
+		if err != nil {
+			return fmt.Errorf("Backup file %q not found: %v", outputPath, err)
+		}
+
+		if info.Size() == 0 {
+			return fmt.Errorf("Backup file %q is empty", outputPath)
+		}
+
+		return nil
+	}
+}
+
+func testAccInstanceBackup_basic(instanceName string, backupName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_backup" "backup1" {
+  name          = "%s"
+  instance      = lxd_instance.instance1.name
+  instance_only = true
+}
+	`, instanceName, acctest.TestImage, backupName)
+}
+
+func testAccInstanceBackup_outputPath(instanceName string, backupName string, outputPath string, generation string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_backup" "backup1" {
+  name                  = "%s"
+  instance              = lxd_instance.instance1.name
+  instance_only         = true
+  compression_algorithm = "gzip"
+  expires_at            = "2099-01-01T00:00:00Z"
+  output_path           = "%s"
+
+  triggers = {
+    generation = "%s"
+  }
+}
+	`, instanceName, acctest.TestImage, backupName, outputPath, generation)
+}
+
+func testAccInstanceBackup_expiresAt(instanceName string, backupName string, expiresAt string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_backup" "backup1" {
+  name       = "%s"
+  instance   = lxd_instance.instance1.name
+  expires_at = "%s"
+}
+	`, instanceName, acctest.TestImage, backupName, expiresAt)
+}
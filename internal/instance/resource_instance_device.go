@@ -141,11 +141,57 @@ func (r *InstanceDeviceResource) Configure(_ context.Context, req resource.Confi
 	r.provider = provider
 }
 
-func (r *InstanceDeviceResource) ModifyPlan(_ context.Context, _ resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+func (r *InstanceDeviceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
 	resp.Diagnostics.AddWarning(
 		"lxd_instance_device is experimental",
 		"lxd_instance_device resource is an experimental feature of Terraform LXD Provider and it may change in the future.",
 	)
+
+	if req.Plan.Raw.IsNull() || r.provider == nil {
+		return
+	}
+
+	mode := r.provider.ConfigValidation()
+	if mode == common.ConfigValidationNone {
+		return
+	}
+
+	var plan, state InstanceDeviceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Validate device properties only when they change.
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() || (plan.Type.Equal(state.Type) && plan.Properties.Equal(state.Properties)) {
+			return
+		}
+	}
+
+	if plan.Remote.IsUnknown() || plan.Project.IsUnknown() || plan.Type.IsUnknown() {
+		return
+	}
+
+	server, err := r.provider.InstanceServer(plan.Remote.ValueString(), plan.Project.ValueString(), "")
+	if err != nil {
+		return
+	}
+
+	// Instance type determines which properties are required. It is
+	// not known if the instance does not exist yet.
+	instanceType := ""
+	if !plan.InstanceName.IsUnknown() {
+		instance, _, err := server.GetInstance(plan.InstanceName.ValueString())
+		if err == nil {
+			instanceType = instance.Type
+		}
+	}
+
+	deviceName := plan.Name.ValueString()
+	resp.Diagnostics.Append(common.ValidateDevice(server, deviceName, plan.Type.ValueString(), plan.Properties, path.Root("properties"), instanceType, mode)...)
 }
 
 func (r InstanceDeviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
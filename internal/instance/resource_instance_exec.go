@@ -0,0 +1,560 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+// InstanceExecModel represents commands executed within an existing LXD
+// instance during the resource lifecycle.
+type InstanceExecModel struct {
+	Instance types.String              `tfsdk:"instance"`
+	Project  types.String              `tfsdk:"project"`
+	Remote   types.String              `tfsdk:"remote"`
+	Triggers types.Map                 `tfsdk:"triggers"`
+	Create   *InstanceExecCommandModel `tfsdk:"create"`
+	Update   *InstanceExecCommandModel `tfsdk:"update"`
+	Destroy  *InstanceExecCommandModel `tfsdk:"destroy"`
+
+	// Timeouts.
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// InstanceExecCommandModel represents a single command block (create,
+// update, or destroy) of the instance exec resource.
+type InstanceExecCommandModel struct {
+	Command         types.List   `tfsdk:"command"`
+	Environment     types.Map    `tfsdk:"environment"`
+	WorkingDir      types.String `tfsdk:"working_dir"`
+	UserID          types.Int64  `tfsdk:"uid"`
+	GroupID         types.Int64  `tfsdk:"gid"`
+	Stdin           types.String `tfsdk:"stdin"`
+	StdinFile       types.String `tfsdk:"stdin_file"`
+	Timeout         types.String `tfsdk:"timeout"`
+	Retries         types.Int64  `tfsdk:"retries"`
+	RetryInterval   types.String `tfsdk:"retry_interval"`
+	RecordOutput    types.Bool   `tfsdk:"record_output"`
+	FailOnError     types.Bool   `tfsdk:"fail_on_error"`
+	MaxOutputBytes  types.Int64  `tfsdk:"max_output_bytes"`
+	SensitiveOutput types.Bool   `tfsdk:"sensitive_output"`
+
+	// Computed.
+	ExitCode        types.Int64  `tfsdk:"exit_code"`
+	Stdout          types.String `tfsdk:"stdout"`
+	Stderr          types.String `tfsdk:"stderr"`
+	SensitiveStdout types.String `tfsdk:"sensitive_stdout"`
+	SensitiveStderr types.String `tfsdk:"sensitive_stderr"`
+}
+
+// Execute runs the command within the instance using the same semantics
+// as instance execs, and populates the computed fields.
+func (m *InstanceExecCommandModel) Execute(ctx context.Context, server lxd.InstanceServer, instanceName string) diag.Diagnostics {
+	environment := m.Environment
+	if environment.IsNull() || environment.IsUnknown() {
+		environment = types.MapValueMust(types.StringType, map[string]attr.Value{})
+	}
+
+	exec := common.ExecModel{
+		Command:         m.Command,
+		Environment:     environment,
+		WorkingDir:      m.WorkingDir,
+		Enabled:         types.BoolValue(true),
+		RecordOutput:    m.RecordOutput,
+		FailOnError:     m.FailOnError,
+		UserID:          m.UserID,
+		GroupID:         m.GroupID,
+		Stdin:           m.Stdin,
+		StdinFile:       m.StdinFile,
+		Timeout:         m.Timeout,
+		Retries:         m.Retries,
+		RetryInterval:   m.RetryInterval,
+		MaxOutputBytes:  m.MaxOutputBytes,
+		SensitiveOutput: m.SensitiveOutput,
+		RunCount:        types.Int64Value(0),
+	}
+
+	diags := exec.Execute(ctx, server, instanceName)
+	if diags.HasError() {
+		return diags
+	}
+
+	m.ExitCode = exec.ExitCode
+	m.Stdout = exec.Output
+	m.Stderr = exec.Error
+	m.SensitiveStdout = exec.SensitiveStdout
+	m.SensitiveStderr = exec.SensitiveStderr
+
+	return diags
+}
+
+// configEqual returns true if the configured fields of both command
+// models are equal.
+func (m *InstanceExecCommandModel) configEqual(o *InstanceExecCommandModel) bool {
+	if o == nil {
+		return false
+	}
+
+	return m.Command.Equal(o.Command) &&
+		m.Environment.Equal(o.Environment) &&
+		m.WorkingDir.Equal(o.WorkingDir) &&
+		m.UserID.Equal(o.UserID) &&
+		m.GroupID.Equal(o.GroupID) &&
+		m.Stdin.Equal(o.Stdin) &&
+		m.StdinFile.Equal(o.StdinFile) &&
+		m.Timeout.Equal(o.Timeout) &&
+		m.Retries.Equal(o.Retries) &&
+		m.RetryInterval.Equal(o.RetryInterval) &&
+		m.RecordOutput.Equal(o.RecordOutput) &&
+		m.FailOnError.Equal(o.FailOnError) &&
+		m.MaxOutputBytes.Equal(o.MaxOutputBytes) &&
+		m.SensitiveOutput.Equal(o.SensitiveOutput)
+}
+
+// resetComputed sets computed fields to values indicating that the
+// command was not executed.
+func (m *InstanceExecCommandModel) resetComputed() {
+	m.ExitCode = types.Int64Value(-1)
+	m.Stdout = types.StringValue("")
+	m.Stderr = types.StringValue("")
+	m.SensitiveStdout = types.StringValue("")
+	m.SensitiveStderr = types.StringValue("")
+}
+
+// copyComputed copies computed fields from the given command model. If the
+// model is nil, the computed fields are reset instead.
+func (m *InstanceExecCommandModel) copyComputed(from *InstanceExecCommandModel) {
+	if from == nil || from.ExitCode.IsNull() || from.ExitCode.IsUnknown() {
+		m.resetComputed()
+		return
+	}
+
+	m.ExitCode = from.ExitCode
+	m.Stdout = from.Stdout
+	m.Stderr = from.Stderr
+	m.SensitiveStdout = from.SensitiveStdout
+	m.SensitiveStderr = from.SensitiveStderr
+}
+
+// InstanceExecResource represent LXD instance exec resource.
+type InstanceExecResource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+// NewInstanceExecResource returns a new instance exec resource.
+func NewInstanceExecResource() resource.Resource {
+	return &InstanceExecResource{}
+}
+
+func (r InstanceExecResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance_exec"
+}
+
+func (r InstanceExecResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the instance in which the commands are executed",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(provider_config.DefaultProject),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"triggers": schema.MapAttribute{
+				Description: "Map of arbitrary values that re-run the update (or create) command when changed",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+
+			// Custom timeouts
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+
+		Blocks: map[string]schema.Block{
+			"create":  instanceExecCommandBlock("Command executed when the resource is created"),
+			"update":  instanceExecCommandBlock("Command executed when triggers or the command change"),
+			"destroy": instanceExecCommandBlock("Command executed when the resource is destroyed"),
+		},
+	}
+}
+
+// instanceExecCommandBlock returns the schema of a single command block.
+func instanceExecCommandBlock(description string) schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: description,
+		Attributes: execSchemaAttributes(map[string]schema.Attribute{
+			"command": schema.ListAttribute{
+				Description: "Command to run within the instance",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+
+			"environment": schema.MapAttribute{
+				Description: "Map of additional environment variables",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"working_dir": schema.StringAttribute{
+				Description: "The directory in which the command should run",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"record_output": schema.BoolAttribute{
+				Description: "Whether to record command's output (stdout and stderr)",
+				Optional:    true,
+			},
+
+			"fail_on_error": schema.BoolAttribute{
+				Description: "Whether to fail on command error",
+				Optional:    true,
+			},
+		}),
+	}
+}
+
+func (r *InstanceExecResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	r.provider = provider
+}
+
+func (r InstanceExecResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	if req.Config.Raw.IsNull() {
+		return
+	}
+
+	var config InstanceExecModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Create == nil && config.Update == nil && config.Destroy == nil {
+		resp.Diagnostics.AddError(
+			"Invalid Configuration",
+			`At least one of "create", "update", or "destroy" blocks must be set.`,
+		)
+		return
+	}
+
+	// SingleNestedBlock attributes cannot be required, therefore
+	// ensure the command is set for each configured block.
+	blocks := []struct {
+		name  string
+		block *InstanceExecCommandModel
+	}{
+		{name: "create", block: config.Create},
+		{name: "update", block: config.Update},
+		{name: "destroy", block: config.Destroy},
+	}
+
+	for _, b := range blocks {
+		if b.block != nil && b.block.Command.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(b.name).AtName("command"),
+				"Missing required argument",
+				fmt.Sprintf("The argument %q is required in the %q block.", "command", b.name),
+			)
+		}
+	}
+}
+
+func (r InstanceExecResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan InstanceExecModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set creation timeout.
+	timeout, diags := plan.Timeouts.Create(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := plan.Instance.ValueString()
+
+	if plan.Create != nil {
+		errDiag := ensureInstanceRunning(server, instanceName)
+		if errDiag != nil {
+			resp.Diagnostics.Append(errDiag)
+			return
+		}
+
+		diags := plan.Create.Execute(ctx, server, instanceName)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	// Commands that were not executed have no output.
+	if plan.Update != nil {
+		plan.Update.resetComputed()
+	}
+
+	if plan.Destroy != nil {
+		plan.Destroy.resetComputed()
+	}
+
+	// Update Terraform state.
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r InstanceExecResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state InstanceExecModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Ensure instance exists. If the instance is removed, the commands
+	// have to be executed again once the instance is recreated.
+	instanceName := state.Instance.ValueString()
+	_, _, err = server.GetInstance(instanceName)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve instance %q", instanceName), err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update executes the update command if triggers or the update command
+// have changed. If the update command is not configured, the create
+// command is executed instead when triggers or the create command have
+// changed. Changes of the destroy command do not execute any command.
+func (r InstanceExecResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan InstanceExecModel
+	var state InstanceExecModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set update timeout.
+	timeout, diags := plan.Timeouts.Update(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Retain outputs of commands from the current state.
+	if plan.Create != nil {
+		plan.Create.copyComputed(state.Create)
+	}
+
+	if plan.Update != nil {
+		plan.Update.copyComputed(state.Update)
+	}
+
+	if plan.Destroy != nil {
+		plan.Destroy.copyComputed(state.Destroy)
+	}
+
+	cmd, prev := plan.Update, state.Update
+	if cmd == nil {
+		cmd, prev = plan.Create, state.Create
+	}
+
+	if cmd != nil && (!plan.Triggers.Equal(state.Triggers) || !cmd.configEqual(prev)) {
+		instanceName := plan.Instance.ValueString()
+
+		errDiag := ensureInstanceRunning(server, instanceName)
+		if errDiag != nil {
+			resp.Diagnostics.Append(errDiag)
+			return
+		}
+
+		diags := cmd.Execute(ctx, server, instanceName)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	// Update Terraform state.
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r InstanceExecResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state InstanceExecModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Destroy == nil {
+		return
+	}
+
+	// Set deletion timeout.
+	timeout, diags := state.Timeouts.Delete(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Instance.ValueString()
+	instanceState, _, err := server.GetInstanceState(instanceName)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			// Nothing to clean up if the instance no longer exists.
+			return
+		}
+
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve state of instance %q", instanceName), err.Error())
+		return
+	}
+
+	if !isInstanceRunning(*instanceState) {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Destroy command not executed on instance %q", instanceName),
+			"The destroy command can only be executed while the instance is running.",
+		)
+		return
+	}
+
+	diags = state.Destroy.Execute(ctx, server, instanceName)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ensureInstanceRunning returns an error diagnostic if the instance with
+// the given name does not exist or is not running.
+func ensureInstanceRunning(server lxd.InstanceServer, instanceName string) diag.Diagnostic {
+	instanceState, _, err := server.GetInstanceState(instanceName)
+	if err != nil {
+		return diag.NewErrorDiagnostic(fmt.Sprintf("Failed to retrieve state of instance %q", instanceName), err.Error())
+	}
+
+	if !isInstanceRunning(*instanceState) {
+		return diag.NewErrorDiagnostic(
+			fmt.Sprintf("Instance %q is not running", instanceName),
+			"Commands can only be executed within a running instance.",
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+package instance_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceExec_basic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceExec_basic(instanceName, "v1", "updated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "instance", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "project", "default"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.stdout", "created\n"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.stderr", ""),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "update.exit_code", "-1"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "update.stdout", ""),
+				),
+			},
+			{
+				// Ensure no changes happen.
+				Config: acctest.Provider() + testAccInstanceExec_basic(instanceName, "v1", "updated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "update.exit_code", "-1"),
+				),
+			},
+			{
+				// Changing triggers runs the update command.
+				Config: acctest.Provider() + testAccInstanceExec_basic(instanceName, "v2", "updated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "triggers.version", "v2"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.stdout", "created\n"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "update.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "update.stdout", "updated v2\n"),
+				),
+			},
+			{
+				// Changing the update command alone runs it.
+				Config: acctest.Provider() + testAccInstanceExec_basic(instanceName, "v2", "changed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "triggers.version", "v2"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.stdout", "created\n"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "update.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "update.stdout", "changed v2\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceExec_createOnTriggers(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceExec_createOnly(instanceName, "v1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.stdout", "v1\n"),
+				),
+			},
+			{
+				// Without an update block, the create command is
+				// executed again when triggers change.
+				Config: acctest.Provider() + testAccInstanceExec_createOnly(instanceName, "v2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.stdout", "v2\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceExec_userAndEnvironment(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceExec_userAndEnvironment(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "create.stdout", "1000:1000:/tmp:bar\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceExec_destroy(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceExec_destroy(instanceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "destroy.exit_code", "-1"),
+				),
+			},
+			{
+				// Removing the exec resource runs the destroy command,
+				// which removes the file created by the create command.
+				Config: acctest.Provider() + testAccInstanceExec_destroy(instanceName, false),
+			},
+			{
+				Config: acctest.Provider() + testAccInstanceExec_destroyCheck(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.check", "create.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.check", "create.stdout", "removed\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceExec_failOnError(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstanceExec_failOnError(instanceName),
+				ExpectError: regexp.MustCompile(`Failed to execute command on instance`),
+			},
+		},
+	})
+}
+
+func TestAccInstanceExec_missingBlocks(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstanceExec_missingBlocks(instanceName),
+				ExpectError: regexp.MustCompile(`At least one of "create", "update", or "destroy" blocks must be set`),
+			},
+		},
+	})
+}
+
+func testAccInstanceExec_basic(name string, version string, message string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_exec" "exec1" {
+  instance = lxd_instance.instance1.name
+
+  triggers = {
+    version = "%s"
+  }
+
+  create {
+    command       = ["echo", "created"]
+    record_output = true
+  }
+
+  update {
+    command       = ["echo", "%s %s"]
+    record_output = true
+  }
+}
+	`, name, acctest.TestImage, version, message, version)
+}
+
+func testAccInstanceExec_createOnly(name string, version string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_exec" "exec1" {
+  instance = lxd_instance.instance1.name
+
+  triggers = {
+    version = "%s"
+  }
+
+  create {
+    command       = ["echo", "%s"]
+    record_output = true
+  }
+}
+	`, name, acctest.TestImage, version, version)
+}
+
+func testAccInstanceExec_userAndEnvironment(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_exec" "exec1" {
+  instance = lxd_instance.instance1.name
+
+  create {
+    command       = ["/bin/sh", "-c", "echo $(id -u):$(id -g):$(pwd):$FOO"]
+    environment   = { "FOO" = "bar" }
+    working_dir   = "/tmp"
+    uid           = 1000
+    gid           = 1000
+    record_output = true
+  }
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstanceExec_destroy(name string, withExec bool) string {
+	config := fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+	`, name, acctest.TestImage)
+
+	if !withExec {
+		return config
+	}
+
+	return config + `
+resource "lxd_instance_exec" "exec1" {
+  instance = lxd_instance.instance1.name
+
+  create {
+    command = ["touch", "/tmp/exec-marker"]
+  }
+
+  destroy {
+    command = ["rm", "/tmp/exec-marker"]
+  }
+}
+	`
+}
+
+func testAccInstanceExec_destroyCheck(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_exec" "check" {
+  instance = lxd_instance.instance1.name
+
+  create {
+    command       = ["/bin/sh", "-c", "test -e /tmp/exec-marker || echo removed"]
+    record_output = true
+  }
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstanceExec_failOnError(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_exec" "exec1" {
+  instance = lxd_instance.instance1.name
+
+  create {
+    command       = ["/bin/sh", "-c", "exit 3"]
+    fail_on_error = true
+  }
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstanceExec_missingBlocks(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_exec" "exec1" {
+  instance = lxd_instance.instance1.name
+}
+	`, name, acctest.TestImage)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -42,6 +44,38 @@ type InstanceFileModel struct {
 	Mode       types.String `tfsdk:"mode"`
 	CreateDirs types.Bool   `tfsdk:"create_directories"`
 	Append     types.Bool   `tfsdk:"append"`
+
+	// Directory uploads.
+	SourceDir types.String `tfsdk:"source_dir"`
+	Exclude   types.List   `tfsdk:"exclude"`
+
+	// Templates.
+	Template types.Bool `tfsdk:"template"`
+
+	// Drift detection.
+	SkipContentCheck types.Bool   `tfsdk:"skip_content_check"`
+	SHA256           types.String `tfsdk:"sha256"`   // Computed.
+	Manifest         types.Map    `tfsdk:"manifest"` // Computed.
+}
+
+// ToFileModel converts the resource model into common.InstanceFileModel.
+func (m InstanceFileModel) ToFileModel() common.InstanceFileModel {
+	return common.InstanceFileModel{
+		Content:          m.Content,
+		SourcePath:       m.SourcePath,
+		TargetPath:       m.TargetPath,
+		UserID:           m.UserID,
+		GroupID:          m.GroupID,
+		Mode:             m.Mode,
+		CreateDirs:       m.CreateDirs,
+		Append:           m.Append,
+		SourceDir:        m.SourceDir,
+		Exclude:          m.Exclude,
+		Template:         m.Template,
+		SkipContentCheck: m.SkipContentCheck,
+		SHA256:           m.SHA256,
+		Manifest:         m.Manifest,
+	}
 }
 
 // InstanceFileResource represent LXD instance file resource.
@@ -108,11 +142,33 @@ func (r InstanceFileResource) Schema(_ context.Context, _ resource.SchemaRequest
 					// produce only one meaningful error.
 					stringvalidator.ExactlyOneOf(
 						path.MatchRoot("source_path"),
+						path.MatchRoot("source_dir"),
 						path.MatchRoot("content"),
 					),
 				},
 			},
 
+			"source_dir": schema.StringAttribute{
+				Optional:    true,
+				Description: "Local directory recursively uploaded to the target path",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("append"),
+					),
+				},
+			},
+
+			"exclude": schema.ListAttribute{
+				Optional:    true,
+				Description: "List of glob patterns excluded from the directory upload",
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.AlsoRequires(path.MatchRoot("source_dir")),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
 			"target_path": schema.StringAttribute{
 				Required: true,
 				PlanModifiers: []planmodifier.String{
@@ -160,6 +216,35 @@ func (r InstanceFileResource) Schema(_ context.Context, _ resource.SchemaRequest
 					boolplanmodifier.RequiresReplace(),
 				},
 			},
+
+			"template": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to render the file content as a template with instance facts",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Bool{
+					boolvalidator.ConflictsWith(path.MatchRoot("source_dir")),
+				},
+			},
+
+			"skip_content_check": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to skip comparing the file content within the instance with the uploaded content",
+			},
+
+			// Computed.
+
+			"sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 checksum of the uploaded file content",
+			},
+
+			"manifest": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Manifest of the uploaded source directory",
+			},
 		},
 	}
 }
@@ -205,25 +290,54 @@ func (r InstanceFileResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	file := common.InstanceFileModel{
-		Content:    plan.Content,
-		SourcePath: plan.SourcePath,
-		TargetPath: plan.TargetPath,
-		UserID:     plan.UserID,
-		GroupID:    plan.GroupID,
-		Mode:       plan.Mode,
-		CreateDirs: plan.CreateDirs,
-		Append:     plan.Append,
+	file := plan.ToFileModel()
+	targetPath := plan.TargetPath.ValueString()
+
+	// Render the template with the current instance facts.
+	if file.IsTemplate() {
+		facts, err := getInstanceFacts(server, instanceName)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve facts of instance %q", instanceName), err.Error())
+			return
+		}
+
+		file, err = common.RenderInstanceFile(file, facts)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to render file %q for instance %q", targetPath, instanceName), err.Error())
+			return
+		}
 	}
 
 	// Upload file.
-	targetPath := plan.TargetPath.ValueString()
 	err = common.InstanceFileUpload(server, instanceName, file)
 	if err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("Failed to create file %q on instance %q", targetPath, instanceName), err.Error())
 		return
 	}
 
+	// Source file content and rendered templates may not be known
+	// until apply.
+	if plan.SHA256.IsUnknown() {
+		hash, err := common.InstanceFileHash(file)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to compute checksum of file %q", targetPath), err.Error())
+			return
+		}
+
+		plan.SHA256 = types.StringValue(hash)
+	}
+
+	if plan.Manifest.IsUnknown() {
+		plan.Manifest = types.MapNull(types.StringType)
+		if file.IsDir() {
+			plan.Manifest, err = common.InstanceDirManifest(file)
+			if err != nil {
+				resp.Diagnostics.AddError(fmt.Sprintf("Failed to compute manifest of directory %q", plan.SourceDir.ValueString()), err.Error())
+				return
+			}
+		}
+	}
+
 	fileID := createFileResourceID(remote, instanceName, targetPath)
 	plan.ResourceID = types.StringValue(fileID)
 
@@ -265,8 +379,10 @@ func (r InstanceFileResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	// Fetch an existing file.
-	_, file, err := server.GetInstanceFile(instanceName, targetPath)
+	// Fetch an existing file. Hash its content only if the content
+	// check is enabled, as reading large files may be expensive.
+	checkContent := state.ToFileModel().IsContentChecked()
+	file, err := common.GetInstanceFileInfo(server, instanceName, targetPath, checkContent)
 	if err != nil {
 		if errors.IsNotFoundError(err) {
 			// If file is not found, remove it from the Terraform state
@@ -283,14 +399,212 @@ func (r InstanceFileResource) Read(ctx context.Context, req resource.ReadRequest
 	state.TargetPath = types.StringValue(targetPath)
 	state.UserID = types.Int64Value(file.UID)
 	state.GroupID = types.Int64Value(file.GID)
-	state.Mode = types.StringValue(fmt.Sprintf("%04o", file.Mode))
+
+	// Permissions of uploaded directory entries are preserved from the
+	// source directory, therefore the mode is not tracked.
+	if !state.ToFileModel().IsDir() {
+		state.Mode = types.StringValue(fmt.Sprintf("%04o", file.Mode))
+	}
+
+	// Store the checksum of the file within the instance. If it does not
+	// match the checksum of the local content, the file is re-uploaded.
+	if checkContent {
+		state.SHA256 = types.StringValue(file.SHA256)
+	}
 
 	// Update Terraform state.
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
 
+// Update synchronizes uploaded directories when the source directory
+// manifest changes. Only entries that changed since the previous upload
+// are transferred. Files are replaced instead, therefore only attributes
+// that do not require the file to be re-uploaded are persisted.
 func (r InstanceFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan InstanceFileModel
+	var state InstanceFileModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ResourceID = state.ResourceID
+
+	file := plan.ToFileModel()
+	if file.IsDir() && (!plan.SHA256.Equal(state.SHA256) || !plan.SourceDir.Equal(state.SourceDir) || !plan.Exclude.Equal(state.Exclude)) {
+		remote := plan.Remote.ValueString()
+		project := plan.Project.ValueString()
+		server, err := r.provider.InstanceServer(remote, project, "")
+		if err != nil {
+			resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+			return
+		}
+
+		instanceName := plan.Instance.ValueString()
+		targetPath := plan.TargetPath.ValueString()
+
+		prevFile := state.ToFileModel()
+		err = common.InstanceDirUpload(server, instanceName, file, &prevFile)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to update directory %q on instance %q", targetPath, instanceName), err.Error())
+			return
+		}
+
+		if plan.SHA256.IsUnknown() {
+			hash, err := common.InstanceFileHash(file)
+			if err != nil {
+				resp.Diagnostics.AddError(fmt.Sprintf("Failed to compute checksum of directory %q", plan.SourceDir.ValueString()), err.Error())
+				return
+			}
+
+			plan.SHA256 = types.StringValue(hash)
+		}
+
+		if plan.Manifest.IsUnknown() {
+			plan.Manifest, err = common.InstanceDirManifest(file)
+			if err != nil {
+				resp.Diagnostics.AddError(fmt.Sprintf("Failed to compute manifest of directory %q", plan.SourceDir.ValueString()), err.Error())
+				return
+			}
+		}
+	}
+
+	if plan.SHA256.IsUnknown() {
+		plan.SHA256 = state.SHA256
+	}
+
+	if plan.Manifest.IsUnknown() {
+		plan.Manifest = state.Manifest
+	}
+
+	// Update Terraform state.
+	diags := resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ModifyPlan computes the checksum of the local file content. If the
+// checksum differs from the checksum of the file within the instance,
+// the file is replaced. Uploaded directories are updated in place.
+func (r InstanceFileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		// Resource is being destroyed.
+		return
+	}
+
+	var plan InstanceFileModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Content.IsUnknown() || plan.SourcePath.IsUnknown() || plan.SourceDir.IsUnknown() || plan.Exclude.IsUnknown() {
+		return
+	}
+
+	// Manifest is recorded only for directory uploads.
+	if !plan.ToFileModel().IsDir() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("manifest"), types.MapNull(types.StringType))...)
+	}
+
+	if plan.ToFileModel().IsTemplate() {
+		r.modifyTemplatePlan(ctx, req, resp)
+		return
+	}
+
+	// Source file may not exist until apply (e.g. if it is generated
+	// by another resource), in which case the checksum remains unknown.
+	hash, err := common.InstanceFileHash(plan.ToFileModel())
+	if err != nil {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sha256"), hash)...)
+
+	if plan.ToFileModel().IsDir() {
+		manifest, err := common.InstanceDirManifest(plan.ToFileModel())
+		if err != nil {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("manifest"), manifest)...)
+	}
+
+	if req.State.Raw.IsNull() {
+		// Resource is being created.
+		return
+	}
+
+	var state InstanceFileModel
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.ToFileModel().IsDir() {
+		return
+	}
+
+	if !state.SHA256.IsNull() && !state.SHA256.IsUnknown() && state.SHA256.ValueString() != hash {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("sha256"))
+	}
+}
+
+// modifyTemplatePlan renders the templated file with the current facts of
+// the instance. If the rendered content differs from the file within the
+// instance (e.g. because the instance facts have changed), the file is
+// replaced. The checksum of the new file remains unknown until apply, as
+// facts may change in the meantime.
+func (r InstanceFileResource) modifyTemplatePlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || r.provider == nil {
+		return
+	}
+
+	var plan InstanceFileModel
+	var state InstanceFileModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote, instanceName, _ := splitFileResourceID(state.ResourceID.ValueString())
+	server, err := r.provider.InstanceServer(remote, state.Project.ValueString(), "")
+	if err != nil {
+		return
+	}
+
+	// Instance may not exist yet, in which case it is recreated
+	// along with the file.
+	facts, err := getInstanceFacts(server, instanceName)
+	if err != nil {
+		return
+	}
+
+	rendered, err := common.RenderInstanceFile(plan.ToFileModel(), facts)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("content"), fmt.Sprintf("Failed to render file %q", plan.TargetPath.ValueString()), err.Error())
+		return
+	}
+
+	hash, err := common.InstanceFileHash(rendered)
+	if err != nil {
+		return
+	}
+
+	if state.SHA256.ValueString() == hash {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sha256"), hash)...)
+		return
+	}
+
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("sha256"))
 }
 
 func (r InstanceFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -324,7 +638,12 @@ func (r InstanceFileResource) Delete(ctx context.Context, req resource.DeleteReq
 	}
 
 	// Delete file.
-	err = common.InstanceFileDelete(server, instanceName, state.TargetPath.ValueString())
+	if state.ToFileModel().IsDir() {
+		err = common.InstanceDirDelete(server, instanceName, state.TargetPath.ValueString())
+	} else {
+		err = common.InstanceFileDelete(server, instanceName, state.TargetPath.ValueString())
+	}
+
 	if err != nil && !errors.IsNotFoundError(err) {
 		resp.Diagnostics.AddError(fmt.Sprintf("Failed to delete file %q from instance %q", targetFile, instanceName), err.Error())
 		return
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
 )
 
@@ -83,6 +84,116 @@ func TestAccInstanceFile_project(t *testing.T) {
 	})
 }
 
+func TestAccInstanceFile_contentDrift(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_content(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "sha256", "c98c24b677eff44860afea6f493bbaec5bb1c4cbb209c6fc2bbb47f66ff2ad31"),
+				),
+			},
+			{
+				// Modify the file within the instance, which
+				// must be detected as a drift.
+				Config:             acctest.Provider() + testAccInstanceFile_contentDrift(instanceName, false),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Ensure the file is re-uploaded and its content
+				// is no longer checked.
+				Config: acctest.Provider() + testAccInstanceFile_contentDrift(instanceName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "skip_content_check", "true"),
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "sha256", "c98c24b677eff44860afea6f493bbaec5bb1c4cbb209c6fc2bbb47f66ff2ad31"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceFile_sourceDir(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_sourceDir(instanceName, `["*.log"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.dir1", "source_dir", "../acctest/fixtures/test-dir"),
+					resource.TestCheckResourceAttr("lxd_instance_file.dir1", "target_path", "/opt/app"),
+					resource.TestCheckResourceAttr("lxd_instance_file.dir1", "exclude.#", "1"),
+					resource.TestCheckResourceAttrSet("lxd_instance_file.dir1", "sha256"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "type", "directory"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "entries.#", "3"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.nested", "content", "Hello from b!\n"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.nested", "mode", "0644"),
+				),
+			},
+			{
+				// Removing the exclude pattern uploads the excluded
+				// file without recreating the resource.
+				Config: acctest.Provider() + testAccInstanceFile_sourceDir(instanceName, `[]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_instance_file.dir1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.dir1", "exclude.#", "0"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "entries.#", "4"),
+				),
+			},
+			{
+				// Excluding the file again removes it from the instance.
+				Config: acctest.Provider() + testAccInstanceFile_sourceDir(instanceName, `["debug.log"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.dir1", "exclude.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "entries.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceFile_template(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_template(instanceName, "dev"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "template", "true"),
+					resource.TestCheckResourceAttrSet("lxd_instance_file.file1", "sha256"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "content", fmt.Sprintf("name=%s\nenv=dev\n", instanceName)),
+				),
+			},
+			{
+				// Changing the instance config changes the facts,
+				// which is detected once the instance is updated.
+				Config:             acctest.Provider() + testAccInstanceFile_template(instanceName, "prod"),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Ensure the file is rendered again.
+				Config: acctest.Provider() + testAccInstanceFile_template(instanceName, "prod"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "content", fmt.Sprintf("name=%s\nenv=prod\n", instanceName)),
+				),
+			},
+		},
+	})
+}
+
 func testAccInstanceFile_content(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -140,3 +251,84 @@ resource "lxd_instance_file" "file1" {
 }
 	`, project, instance, acctest.TestImage)
 }
+
+func testAccInstanceFile_contentDrift(name string, skipContentCheck bool) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_file" "file1" {
+  instance           = lxd_instance.instance1.name
+  content            = "Hello, World!\n"
+  target_path        = "/foo/bar.txt"
+  create_directories = true
+  skip_content_check = %t
+}
+
+resource "lxd_instance_exec" "modify" {
+  instance = lxd_instance_file.file1.instance
+
+  create {
+    command = ["/bin/sh", "-c", "echo modified > /foo/bar.txt"]
+  }
+}
+	`, name, acctest.TestImage, skipContentCheck)
+}
+
+func testAccInstanceFile_sourceDir(name string, exclude string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_file" "dir1" {
+  instance           = lxd_instance.instance1.name
+  source_dir         = "../acctest/fixtures/test-dir"
+  target_path        = "/opt/app"
+  exclude            = %s
+  create_directories = true
+}
+
+data "lxd_instance_file" "dir" {
+  instance   = lxd_instance_file.dir1.instance
+  path       = lxd_instance_file.dir1.target_path
+  depends_on = [lxd_instance_file.dir1]
+}
+
+data "lxd_instance_file" "nested" {
+  instance   = lxd_instance_file.dir1.instance
+  path       = "/opt/app/sub/b.txt"
+  depends_on = [lxd_instance_file.dir1]
+}
+	`, name, acctest.TestImage, exclude)
+}
+
+func testAccInstanceFile_template(name string, env string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  config = {
+    "user.env" = "%s"
+  }
+}
+
+resource "lxd_instance_file" "file1" {
+  instance           = lxd_instance.instance1.name
+  content            = "name={{ .name }}\nenv={{ index .config \"user.env\" }}\n"
+  target_path        = "/etc/app.conf"
+  template           = true
+  create_directories = true
+}
+
+data "lxd_instance_file" "file" {
+  instance   = lxd_instance_file.file1.instance
+  path       = lxd_instance_file.file1.target_path
+  depends_on = [lxd_instance_file.file1]
+}
+	`, name, acctest.TestImage, env)
+}
@@ -0,0 +1,1169 @@
+package instance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+// Config keys used to track members of an instance group.
+const (
+	instanceGroupKey         = "user.instance-group"
+	instanceGroupIndexKey    = "user.instance-group.index"
+	instanceGroupRevisionKey = "user.instance-group.revision"
+)
+
+// Instance group update strategies.
+const (
+	instanceGroupStrategyReplace = "replace"
+	instanceGroupStrategyRebuild = "rebuild"
+)
+
+type InstanceGroupModel struct {
+	Name           types.String `tfsdk:"name"`
+	InstanceCount  types.Int64  `tfsdk:"instance_count"`
+	NamePattern    types.String `tfsdk:"name_pattern"`
+	Description    types.String `tfsdk:"description"`
+	Type           types.String `tfsdk:"type"`
+	Image          types.String `tfsdk:"image"`
+	Profiles       types.List   `tfsdk:"profiles"`
+	Devices        types.Set    `tfsdk:"device"`
+	Config         types.Map    `tfsdk:"config"`
+	Labels         types.Map    `tfsdk:"labels"`
+	Targets        types.List   `tfsdk:"targets"`
+	UpdateStrategy types.String `tfsdk:"update_strategy"`
+	MaxUnavailable types.Int64  `tfsdk:"max_unavailable"`
+	WaitForConfigs types.Set    `tfsdk:"wait_for"`
+	Project        types.String `tfsdk:"project"`
+	Remote         types.String `tfsdk:"remote"`
+
+	// Computed.
+	Revision  types.String `tfsdk:"revision"`
+	Instances types.List   `tfsdk:"instances"`
+
+	// Timeouts.
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// InstanceGroupMemberModel represents a single element of the computed
+// instances list.
+type InstanceGroupMemberModel struct {
+	Name     types.String `tfsdk:"name"`
+	Index    types.Int64  `tfsdk:"index"`
+	Location types.String `tfsdk:"location"`
+	Status   types.String `tfsdk:"status"`
+	Revision types.String `tfsdk:"revision"`
+	IPv4     types.String `tfsdk:"ipv4_address"`
+	IPv6     types.String `tfsdk:"ipv6_address"`
+}
+
+var instanceGroupMemberObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name":         types.StringType,
+		"index":        types.Int64Type,
+		"location":     types.StringType,
+		"status":       types.StringType,
+		"revision":     types.StringType,
+		"ipv4_address": types.StringType,
+		"ipv6_address": types.StringType,
+	},
+}
+
+// instanceGroupTemplate is the instance template of the group. Its hash
+// is stored on each member to detect members that are out of date.
+type instanceGroupTemplate struct {
+	Description string                       `json:"description"`
+	Type        string                       `json:"type"`
+	Image       string                       `json:"image"`
+	Profiles    []string                     `json:"profiles"`
+	Config      map[string]string            `json:"config"`
+	Devices     map[string]map[string]string `json:"devices"`
+}
+
+// InstanceGroupResource represent LXD instance group resource.
+type InstanceGroupResource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+// NewInstanceGroupResource returns a new instance group resource.
+func NewInstanceGroupResource() resource.Resource {
+	return &InstanceGroupResource{}
+}
+
+func (r InstanceGroupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance_group"
+}
+
+func (r InstanceGroupResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"instance_count": schema.Int64Attribute{
+				Description: "Number of instances in the group",
+				Required:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+
+			"name_pattern": schema.StringAttribute{
+				Description: "Pattern of the instance names, where %d is replaced with the instance index",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					namePatternValidator{},
+				},
+			},
+
+			"description": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(""),
+			},
+
+			"type": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("container"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("container", "virtual-machine"),
+				},
+			},
+
+			"image": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			// If profiles are null, use "default" profile.
+			// If profiles lengeth is 0, no profiles are applied.
+			"profiles": schema.ListAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					// Prevent empty values.
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"config": schema.MapAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: common.ConfigValueType{},
+				Default:     mapdefault.StaticValue(types.MapValueMust(common.ConfigValueType{}, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(configKeyValidator{}, common.NoLabelKeyValidator{}, instanceGroupKeyValidator{}),
+				},
+			},
+
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels of the group instances, stored as user.label.* config keys",
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					// Prevent empty values.
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"targets": schema.ListAttribute{
+				Description: "Cluster members or groups (prefixed with @) the instances are spread across",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"update_strategy": schema.StringAttribute{
+				Description: "How out of date instances are updated: replace (default) or rebuild",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(instanceGroupStrategyReplace),
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						instanceGroupStrategyReplace,
+						instanceGroupStrategyRebuild,
+					),
+				},
+			},
+
+			"max_unavailable": schema.Int64Attribute{
+				Description: "Maximum number of instances updated at the same time",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(provider_config.DefaultProject),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// Computed.
+
+			"revision": schema.StringAttribute{
+				Description: "Revision of the instance template",
+				Computed:    true,
+			},
+
+			"instances": schema.ListNestedAttribute{
+				Description: "Instances of the group ordered by index",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"index": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"location": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"status": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"revision": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"ipv4_address": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"ipv6_address": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			// Custom timeouts
+			"timeouts": timeouts.AttributesAll(ctx),
+		},
+
+		Blocks: map[string]schema.Block{
+			"wait_for": waitForSchemaBlock(),
+
+			"device": deviceSchemaBlock(),
+		},
+	}
+}
+
+func (r *InstanceGroupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	r.provider = provider
+}
+
+func (r InstanceGroupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	if req.Config.Raw.IsNull() {
+		return
+	}
+
+	var config InstanceGroupModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.WaitForConfigs.IsNull() && !config.WaitForConfigs.IsUnknown() {
+		validateWaitFor(ctx, InstanceModel{Type: config.Type, WaitForConfigs: config.WaitForConfigs}, resp)
+	}
+}
+
+func (r *InstanceGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan InstanceGroupModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// If profiles in config are null, set "default" profile in plan.
+	if config.Profiles.IsNull() {
+		plan.Profiles = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("default")})
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("profiles"), plan.Profiles)...)
+	}
+
+	// If name pattern is not set, derive it from the group name. If the
+	// name is not known yet, the name pattern is derived during apply.
+	if config.NamePattern.IsNull() && !plan.Name.IsUnknown() {
+		plan.NamePattern = types.StringValue(defaultNamePattern(plan.Name.ValueString()))
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name_pattern"), plan.NamePattern)...)
+	}
+
+	resp.Diagnostics.Append(r.validatePlanConfig(ctx, req)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Compute the revision of the instance template, so that the
+	// rolling update of existing instances is visible in the plan.
+	revision := types.StringUnknown()
+	if plan.isTemplateKnown(ctx) {
+		template, diags := plan.template(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		revision = types.StringValue(template.revision())
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("revision"), revision)...)
+}
+
+// validatePlanConfig validates the config keys and device properties of the
+// planned instance template against the server metadata. Validation is
+// skipped for config and devices that are unchanged.
+func (r *InstanceGroupResource) validatePlanConfig(ctx context.Context, req resource.ModifyPlanRequest) diag.Diagnostics {
+	var plan, state InstanceGroupModel
+
+	if r.provider == nil || r.provider.ConfigValidation() == common.ConfigValidationNone {
+		return nil
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	if diags.HasError() {
+		return diags
+	}
+
+	configChanged := true
+	devicesChanged := true
+
+	if !req.State.Raw.IsNull() {
+		diags := req.State.Get(ctx, &state)
+		if diags.HasError() {
+			return diags
+		}
+
+		configChanged = !plan.Config.Equal(state.Config)
+		devicesChanged = !plan.Devices.Equal(state.Devices)
+	}
+
+	if (!configChanged && !devicesChanged) || plan.Remote.IsUnknown() {
+		return nil
+	}
+
+	// Config keys and device properties do not depend on the project.
+	server, err := r.provider.InstanceServer(plan.Remote.ValueString(), "", "")
+	if err != nil {
+		return nil
+	}
+
+	mode := r.provider.ConfigValidation()
+
+	if configChanged {
+		diags.Append(common.ValidateServerConfig(server, plan.Config, "instance", "", mode)...)
+	}
+
+	if devicesChanged {
+		diags.Append(common.ValidateDevices(ctx, server, plan.Devices, path.Root("device"), plan.Type.ValueString(), mode)...)
+	}
+
+	return diags
+}
+
+func (r InstanceGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan InstanceGroupModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set creation timeout.
+	timeout, diags := plan.Timeouts.Create(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Derive the name pattern if it was unknown during plan.
+	if plan.NamePattern.IsUnknown() {
+		plan.NamePattern = types.StringValue(defaultNamePattern(plan.Name.ValueString()))
+	}
+
+	// Instances that are created before an error occurs are recorded
+	// in the state, therefore state is synced regardless of the error.
+	// Terraform marks the group as tainted in such case, so that it is
+	// replaced on the next apply.
+	resp.Diagnostics.Append(r.reconcile(ctx, server, plan)...)
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r InstanceGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state InstanceGroupModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set read timeout.
+	timeout, diags := state.Timeouts.Read(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update scales the group to the desired number of instances and rolls
+// out the instance template to the instances that are out of date.
+func (r InstanceGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan InstanceGroupModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set update timeout.
+	timeout, diags := plan.Timeouts.Update(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Derive the name pattern if it was unknown during plan.
+	if plan.NamePattern.IsUnknown() {
+		plan.NamePattern = types.StringValue(defaultNamePattern(plan.Name.ValueString()))
+	}
+
+	resp.Diagnostics.Append(r.reconcile(ctx, server, plan)...)
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r InstanceGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state InstanceGroupModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set deletion timeout.
+	timeout, diags := state.Timeouts.Delete(ctx, r.provider.DefaultTimeout())
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	groupName := state.Name.ValueString()
+	members, err := getInstanceGroupMembers(server, groupName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve instances of group %q", groupName), err.Error())
+		return
+	}
+
+	for _, index := range slices.Backward(sortedMemberIndexes(members)) {
+		resp.Diagnostics.Append(deleteGroupInstance(ctx, server, members[index].Name)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+}
+
+// SyncState fetches the instances of the group and updates the
+// Terraform state. The instance count reflects the number of existing instances
+// and the revision is known only if all instances share the same
+// revision. This way, partially applied changes are retried on the next
+// apply.
+func (r InstanceGroupResource) SyncState(ctx context.Context, tfState *tfsdk.State, server lxd.InstanceServer, m InstanceGroupModel) diag.Diagnostics {
+	var respDiags diag.Diagnostics
+
+	groupName := m.Name.ValueString()
+	members, err := getInstanceGroupMembers(server, groupName)
+	if err != nil {
+		respDiags.AddError(fmt.Sprintf("Failed to retrieve instances of group %q", groupName), err.Error())
+		return respDiags
+	}
+
+	revisions := make(map[string]bool)
+	instances := make([]InstanceGroupMemberModel, 0, len(members))
+	for _, index := range sortedMemberIndexes(members) {
+		inst := members[index]
+		revision := inst.Config[instanceGroupRevisionKey]
+		revisions[revision] = true
+
+		member := InstanceGroupMemberModel{
+			Name:     types.StringValue(inst.Name),
+			Index:    types.Int64Value(int64(index)),
+			Location: types.StringValue(inst.Location),
+			Status:   types.StringValue(inst.Status),
+			Revision: types.StringValue(revision),
+			IPv4:     types.StringNull(),
+			IPv6:     types.StringNull(),
+		}
+
+		if inst.State != nil {
+			ipv4, ipv6, _ := findAccessAddresses(inst.Instance, *inst.State)
+			if ipv4 != "" {
+				member.IPv4 = types.StringValue(ipv4)
+			}
+
+			if ipv6 != "" {
+				member.IPv6 = types.StringValue(ipv6)
+			}
+		}
+
+		instances = append(instances, member)
+	}
+
+	switch len(revisions) {
+	case 0:
+		// Revision of an empty group is the revision of its template.
+		template, diags := m.template(ctx)
+		if diags.HasError() {
+			return diags
+		}
+
+		m.Revision = types.StringValue(template.revision())
+	case 1:
+		m.Revision = types.StringValue(slices.Collect(maps.Keys(revisions))[0])
+	default:
+		m.Revision = types.StringValue("")
+	}
+
+	m.InstanceCount = types.Int64Value(int64(len(members)))
+
+	instanceList, diags := types.ListValueFrom(ctx, instanceGroupMemberObjectType, instances)
+	respDiags.Append(diags...)
+
+	m.Instances = instanceList
+
+	if respDiags.HasError() {
+		return respDiags
+	}
+
+	return tfState.Set(ctx, &m)
+}
+
+// reconcile creates missing instances, rolls out the instance template
+// to the instances that are out of date and removes surplus instances.
+func (r InstanceGroupResource) reconcile(ctx context.Context, server lxd.InstanceServer, m InstanceGroupModel) diag.Diagnostics {
+	groupName := m.Name.ValueString()
+	count := int(m.InstanceCount.ValueInt64())
+
+	template, diags := m.template(ctx)
+	if diags.HasError() {
+		return diags
+	}
+
+	targets, diags := m.targets(ctx)
+	if diags.HasError() {
+		return diags
+	}
+
+	revision := template.revision()
+
+	members, err := getInstanceGroupMembers(server, groupName)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to retrieve instances of group %q", groupName), err.Error())
+		return diags
+	}
+
+	// Create missing instances.
+	for index := range count {
+		_, ok := members[index]
+		if ok {
+			continue
+		}
+
+		name := m.instanceName(index)
+
+		diags.Append(r.createGroupInstance(ctx, server, m, template, index, targetForIndex(targets, index))...)
+		if diags.HasError() {
+			return diags
+		}
+
+		diags.Append(waitFor(ctx, server, name, m.WaitForConfigs)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	// Find instances that are out of date.
+	outdated := make([]int, 0, len(members))
+	for _, index := range sortedMemberIndexes(members) {
+		if index < count && members[index].Config[instanceGroupRevisionKey] != revision {
+			outdated = append(outdated, index)
+		}
+	}
+
+	if len(outdated) > 0 && m.UpdateStrategy.ValueString() == instanceGroupStrategyRebuild {
+		diags.Append(common.CheckAttributeExtensions(server, path.Root("update_strategy"), "instances_rebuild")...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	// Update instances in batches of at most max_unavailable instances.
+	// Each batch must pass the wait_for conditions before the next one
+	// is taken down.
+	for batch := range slices.Chunk(outdated, int(m.MaxUnavailable.ValueInt64())) {
+		for _, index := range batch {
+			inst := members[index]
+
+			if m.UpdateStrategy.ValueString() == instanceGroupStrategyRebuild {
+				_, diag := stopInstance(ctx, server, inst.Name, false)
+				if diag != nil {
+					diags.Append(diag)
+					return diags
+				}
+			} else {
+				diags.Append(deleteGroupInstance(ctx, server, inst.Name)...)
+				if diags.HasError() {
+					return diags
+				}
+			}
+		}
+
+		for _, index := range batch {
+			if m.UpdateStrategy.ValueString() == instanceGroupStrategyRebuild {
+				diags.Append(r.rebuildGroupInstance(ctx, server, m, template, index)...)
+			} else {
+				diags.Append(r.createGroupInstance(ctx, server, m, template, index, targetForIndex(targets, index))...)
+			}
+
+			if diags.HasError() {
+				return diags
+			}
+		}
+
+		for _, index := range batch {
+			diags.Append(waitFor(ctx, server, members[index].Name, m.WaitForConfigs)...)
+			if diags.HasError() {
+				return diags
+			}
+		}
+	}
+
+	// Remove surplus instances, starting with the highest index.
+	for _, index := range slices.Backward(sortedMemberIndexes(members)) {
+		if index < count {
+			continue
+		}
+
+		diags.Append(deleteGroupInstance(ctx, server, members[index].Name)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	return diags
+}
+
+// createGroupInstance creates and starts the group instance with the
+// given index from the instance template.
+func (r InstanceGroupResource) createGroupInstance(ctx context.Context, server lxd.InstanceServer, m InstanceGroupModel, template instanceGroupTemplate, index int, target string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	imageServer, image, err := r.imageServer(server, template.Image)
+	if err != nil {
+		diags.Append(errors.NewImageServerError(err))
+		return diags
+	}
+
+	instance := api.InstancesPost{
+		Name:        m.instanceName(index),
+		Type:        api.InstanceType(template.Type),
+		InstancePut: template.instancePut(m.Name.ValueString(), index),
+	}
+
+	// Gather info about source image.
+	var imageInfo *api.Image
+	conn, _ := imageServer.GetConnectionInfo()
+
+	if conn.Protocol == "simplestreams" {
+		// Optimisation for simplestreams.
+		imageInfo = &api.Image{}
+		imageInfo.Public = true
+		imageInfo.Fingerprint = image
+		instance.Source.Alias = image
+	} else {
+		// Attempt to resolve an image alias.
+		alias, _, err := imageServer.GetImageAlias(image)
+		if err == nil {
+			image = alias.Target
+			instance.Source.Alias = image
+		}
+
+		// Get the image info.
+		imageInfo, _, err = imageServer.GetImage(image)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to retrieve image info for instance %q", instance.Name), err.Error())
+			return diags
+		}
+	}
+
+	op, err := server.UseTarget(target).CreateInstanceFromImage(imageServer, *imageInfo, instance)
+	if err == nil {
+		err = op.Wait()
+	}
+
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to create instance %q", instance.Name), err.Error())
+		return diags
+	}
+
+	diag := startInstance(ctx, server, instance.Name)
+	if diag != nil {
+		diags.Append(diag)
+	}
+
+	return diags
+}
+
+// rebuildGroupInstance applies the instance template to the stopped
+// group instance with the given index, rebuilds its root disk from the
+// template image and starts it. The instance keeps its location.
+func (r InstanceGroupResource) rebuildGroupInstance(ctx context.Context, server lxd.InstanceServer, m InstanceGroupModel, template instanceGroupTemplate, index int) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	instanceName := m.instanceName(index)
+
+	instance, etag, err := server.GetInstance(instanceName)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to retrieve existing instance %q", instanceName), err.Error())
+		return diags
+	}
+
+	newInstance := template.instancePut(m.Name.ValueString(), index)
+	newInstance.Config = common.MergeConfig(instance.Config, newInstance.Config, InstanceModel{}.ComputedKeys())
+
+	op, err := server.UpdateInstance(instanceName, newInstance, etag)
+	if err == nil {
+		err = op.WaitContext(ctx)
+	}
+
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to update instance %q", instanceName), err.Error())
+		return diags
+	}
+
+	imageServer, image, err := r.imageServer(server, template.Image)
+	if err != nil {
+		diags.Append(errors.NewImageServerError(err))
+		return diags
+	}
+
+	imageInfo := &api.Image{}
+	conn, _ := imageServer.GetConnectionInfo()
+
+	if conn.Protocol == "simplestreams" {
+		imageInfo.Public = true
+		imageInfo.Fingerprint = image
+	} else {
+		alias, _, err := imageServer.GetImageAlias(image)
+		if err == nil {
+			image = alias.Target
+		}
+
+		imageInfo, _, err = imageServer.GetImage(image)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to retrieve image info for instance %q", instanceName), err.Error())
+			return diags
+		}
+	}
+
+	rebuildReq := api.InstanceRebuildPost{
+		Source: api.InstanceSource{
+			Type:  api.SourceTypeImage,
+			Alias: image,
+		},
+	}
+
+	opRebuild, err := server.RebuildInstanceFromImage(imageServer, *imageInfo, instanceName, rebuildReq)
+	if err == nil {
+		err = opRebuild.Wait()
+	}
+
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to rebuild instance %q", instanceName), err.Error())
+		return diags
+	}
+
+	diag := startInstance(ctx, server, instanceName)
+	if diag != nil {
+		diags.Append(diag)
+	}
+
+	return diags
+}
+
+// imageServer returns the image server and the image name of the given
+// image reference in the "[remote:]image" format.
+func (r InstanceGroupResource) imageServer(server lxd.InstanceServer, image string) (lxd.ImageServer, string, error) {
+	imageRemote, imageName, ok := strings.Cut(image, ":")
+	if !ok || imageRemote == "" {
+		// Use the instance server as an image server if image remote is empty.
+		return server, strings.TrimPrefix(image, ":"), nil
+	}
+
+	imageServer, err := r.provider.ImageServer(imageRemote)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return imageServer, imageName, nil
+}
+
+// deleteGroupInstance force stops and deletes the instance with the
+// given name.
+func deleteGroupInstance(ctx context.Context, server lxd.InstanceServer, instanceName string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	isFound, diag := stopInstance(ctx, server, instanceName, true)
+	if diag != nil {
+		if isFound {
+			diags.Append(diag)
+		}
+
+		return diags
+	}
+
+	op, err := server.DeleteInstance(instanceName, false)
+	if err == nil {
+		err = op.WaitContext(ctx)
+	}
+
+	if err != nil && !errors.IsNotFoundError(err) {
+		diags.AddError(fmt.Sprintf("Failed to remove instance %q", instanceName), err.Error())
+	}
+
+	return diags
+}
+
+// getInstanceGroupMembers returns the instances of the group with the
+// given name, keyed by their index within the group.
+func getInstanceGroupMembers(server lxd.InstanceServer, groupName string) (map[int]api.InstanceFull, error) {
+	instances, err := server.GetInstancesFull(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[int]api.InstanceFull)
+	for _, inst := range instances {
+		if inst.Config[instanceGroupKey] != groupName {
+			continue
+		}
+
+		index, err := strconv.Atoi(inst.Config[instanceGroupIndexKey])
+		if err != nil {
+			return nil, fmt.Errorf("Instance %q has invalid group index %q", inst.Name, inst.Config[instanceGroupIndexKey])
+		}
+
+		members[index] = inst
+	}
+
+	return members, nil
+}
+
+// sortedMemberIndexes returns the indexes of the group members in
+// ascending order.
+func sortedMemberIndexes(members map[int]api.InstanceFull) []int {
+	return slices.Sorted(maps.Keys(members))
+}
+
+// targetForIndex returns the target of the instance with the given
+// index. Instances are assigned to the targets in a round-robin fashion.
+func targetForIndex(targets []string, index int) string {
+	if len(targets) == 0 {
+		return ""
+	}
+
+	return targets[index%len(targets)]
+}
+
+// defaultNamePattern returns the name pattern of the instances of the
+// group with the given name, used when the name pattern is not set.
+func defaultNamePattern(groupName string) string {
+	return groupName + "-%d"
+}
+
+// instanceName returns the name of the group instance with the given
+// index.
+func (m InstanceGroupModel) instanceName(index int) string {
+	return fmt.Sprintf(m.NamePattern.ValueString(), index)
+}
+
+// targets returns the list of targets the instances are spread across.
+func (m InstanceGroupModel) targets(ctx context.Context) ([]string, diag.Diagnostics) {
+	if m.Targets.IsNull() || m.Targets.IsUnknown() {
+		return nil, nil
+	}
+
+	targets := make([]string, 0, len(m.Targets.Elements()))
+	diags := m.Targets.ElementsAs(ctx, &targets, false)
+
+	return targets, diags
+}
+
+// isTemplateKnown returns true if all attributes of the instance
+// template are fully known.
+func (m InstanceGroupModel) isTemplateKnown(ctx context.Context) bool {
+	values := []attr.Value{m.Description, m.Type, m.Image, m.Profiles, m.Config, m.Labels, m.Devices}
+	for _, v := range values {
+		tfValue, err := v.ToTerraformValue(ctx)
+		if err != nil || !tfValue.IsFullyKnown() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// template returns the instance template of the group.
+func (m InstanceGroupModel) template(ctx context.Context) (instanceGroupTemplate, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	profiles, d := ToProfileList(ctx, m.Profiles)
+	diags.Append(d...)
+
+	devices, d := common.ToDeviceMap(ctx, m.Devices)
+	diags.Append(d...)
+
+	config, d := common.ToConfigMap(ctx, m.Config)
+	diags.Append(d...)
+
+	labels, d := common.ToConfigMap(ctx, m.Labels)
+	diags.Append(d...)
+
+	maps.Copy(config, common.LabelsToConfig(labels))
+
+	template := instanceGroupTemplate{
+		Description: m.Description.ValueString(),
+		Type:        m.Type.ValueString(),
+		Image:       m.Image.ValueString(),
+		Profiles:    profiles,
+		Config:      config,
+		Devices:     devices,
+	}
+
+	return template, diags
+}
+
+// revision returns the hash of the instance template.
+func (t instanceGroupTemplate) revision() string {
+	// Maps are marshalled with sorted keys, therefore the hash is stable.
+	data, _ := json.Marshal(t)
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])[:12]
+}
+
+// instancePut returns the instance configuration of the group instance
+// with the given index.
+func (t instanceGroupTemplate) instancePut(groupName string, index int) api.InstancePut {
+	config := maps.Clone(t.Config)
+	config[instanceGroupKey] = groupName
+	config[instanceGroupIndexKey] = strconv.Itoa(index)
+	config[instanceGroupRevisionKey] = t.revision()
+
+	devices := make(map[string]map[string]string, len(t.Devices))
+	for name, device := range t.Devices {
+		device = maps.Clone(device)
+
+		// Mark the device as managed by terraform to differentiate between
+		// devices added by terraform and devices added manually.
+		device[common.UserManagedBy] = common.DeviceManagedByTerraform
+		devices[name] = device
+	}
+
+	return api.InstancePut{
+		Description: t.Description,
+		Config:      config,
+		Profiles:    t.Profiles,
+		Devices:     devices,
+	}
+}
+
+// namePatternValidator ensures the instance name pattern contains exactly
+// one %d verb and no other verbs.
+type namePatternValidator struct{}
+
+func (v namePatternValidator) Description(ctx context.Context) string {
+	return "name pattern must contain exactly one %d verb"
+}
+
+func (v namePatternValidator) MarkdownDescription(ctx context.Context) string {
+	return "name pattern must contain exactly one `%d` verb"
+}
+
+func (v namePatternValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if strings.Count(value, "%") != 1 || strings.Count(value, "%d") != 1 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid name pattern",
+			fmt.Sprintf("Name pattern must contain exactly one %%d verb. Got: %q.", value),
+		)
+	}
+}
+
+// instanceGroupKeyValidator ensures config key is not used to track
+// members of the instance group.
+type instanceGroupKeyValidator struct{}
+
+func (v instanceGroupKeyValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("config key cannot have %q prefix", instanceGroupKey)
+}
+
+func (v instanceGroupKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("config key cannot have `%s` prefix", instanceGroupKey)
+}
+
+func (v instanceGroupKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	value := req.ConfigValue.ValueString()
+
+	if strings.HasPrefix(value, instanceGroupKey) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid config key",
+			fmt.Sprintf("Config key cannot have %q prefix, as it is used to track instances of the group. Got: %q.", instanceGroupKey, value),
+		)
+	}
+}
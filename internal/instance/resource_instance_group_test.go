@@ -0,0 +1,201 @@
+package instance_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceGroup_scale(t *testing.T) {
+	groupName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceGroup_basic(groupName, 2, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "name", groupName),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instance_count", "2"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "name_pattern", groupName+"-%d"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.#", "2"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.0.name", groupName+"-0"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.0.status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.1.name", groupName+"-1"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.1.status", "Running"),
+					resource.TestCheckResourceAttrSet("lxd_instance_group.group1", "revision"),
+				),
+			},
+			{
+				// Scale up.
+				Config: acctest.Provider() + testAccInstanceGroup_basic(groupName, 3, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instance_count", "3"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.#", "3"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.2.name", groupName+"-2"),
+				),
+			},
+			{
+				// Scale down.
+				Config: acctest.Provider() + testAccInstanceGroup_basic(groupName, 1, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instance_count", "1"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.#", "1"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.0.name", groupName+"-0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceGroup_partialCreate(t *testing.T) {
+	groupName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Instance with index 1 cannot be created, because an
+				// instance with the same name already exists.
+				Config:      acctest.Provider() + testAccInstanceGroup_partialCreate(groupName, 2),
+				ExpectError: regexp.MustCompile(`Failed to create instance`),
+			},
+			{
+				// Tainted group is replaced on the next apply.
+				Config: acctest.Provider() + testAccInstanceGroup_partialCreate(groupName, 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_instance_group.group1", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instance_count", "1"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.#", "1"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.0.name", groupName+"-0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceGroup_rollingUpdate(t *testing.T) {
+	groupName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceGroup_basic(groupName, 2, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.#", "2"),
+					resource.TestCheckResourceAttrPair("lxd_instance_group.group1", "revision", "lxd_instance_group.group1", "instances.0.revision"),
+					resource.TestCheckResourceAttrPair("lxd_instance_group.group1", "revision", "lxd_instance_group.group1", "instances.1.revision"),
+				),
+			},
+			{
+				// Changing the template updates all instances.
+				Config: acctest.Provider() + testAccInstanceGroup_basic(groupName, 2, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "config.limits.cpu", "2"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.#", "2"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.0.status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.1.status", "Running"),
+					resource.TestCheckResourceAttrPair("lxd_instance_group.group1", "revision", "lxd_instance_group.group1", "instances.0.revision"),
+					resource.TestCheckResourceAttrPair("lxd_instance_group.group1", "revision", "lxd_instance_group.group1", "instances.1.revision"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceGroup_rebuild(t *testing.T) {
+	groupName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.PreCheckAPIExtensions(t, "instances_rebuild")
+		},
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceGroup_rebuild(groupName, "one"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "update_strategy", "rebuild"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "max_unavailable", "2"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.#", "2"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstanceGroup_rebuild(groupName, "two"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "labels.version", "two"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.#", "2"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.0.status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance_group.group1", "instances.1.status", "Running"),
+					resource.TestCheckResourceAttrPair("lxd_instance_group.group1", "revision", "lxd_instance_group.group1", "instances.0.revision"),
+					resource.TestCheckResourceAttrPair("lxd_instance_group.group1", "revision", "lxd_instance_group.group1", "instances.1.revision"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstanceGroup_basic(name string, count int, cpus string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance_group" "group1" {
+  name           = "%s"
+  instance_count = %d
+  image          = "%s"
+
+  config = {
+    "limits.cpu" = "%s"
+  }
+
+  wait_for {
+    type = "ipv4"
+  }
+}
+	`, name, count, acctest.TestImage, cpus)
+}
+
+func testAccInstanceGroup_partialCreate(name string, count int) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%[1]s-1"
+  image   = "%[3]s"
+  running = false
+}
+
+resource "lxd_instance_group" "group1" {
+  name           = "%[1]s"
+  instance_count = %[2]d
+  image          = "%[3]s"
+
+  depends_on = [lxd_instance.instance1]
+}
+	`, name, count, acctest.TestImage)
+}
+
+func testAccInstanceGroup_rebuild(name string, version string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance_group" "group1" {
+  name            = "%s"
+  instance_count  = 2
+  image           = "%s"
+  update_strategy = "rebuild"
+  max_unavailable = 2
+
+  labels = {
+    version = "%s"
+  }
+}
+	`, name, acctest.TestImage, version)
+}
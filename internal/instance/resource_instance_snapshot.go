@@ -27,11 +27,15 @@ import (
 )
 
 type InstanceSnapshotModel struct {
-	Name     types.String `tfsdk:"name"`
-	Instance types.String `tfsdk:"instance"`
-	Stateful types.Bool   `tfsdk:"stateful"`
-	Project  types.String `tfsdk:"project"`
-	Remote   types.String `tfsdk:"remote"`
+	Name            types.String `tfsdk:"name"`
+	Instance        types.String `tfsdk:"instance"`
+	Description     types.String `tfsdk:"description"`
+	Stateful        types.Bool   `tfsdk:"stateful"`
+	ExpiresAt       types.String `tfsdk:"expires_at"`
+	RestoreOnCreate types.Bool   `tfsdk:"restore_instance_on_create"`
+	Restore         types.String `tfsdk:"restore"`
+	Project         types.String `tfsdk:"project"`
+	Remote          types.String `tfsdk:"remote"`
 
 	// Computed.
 	CreatedAt types.Int64 `tfsdk:"created_at"`
@@ -56,9 +60,6 @@ func (r InstanceSnapshotResource) Schema(_ context.Context, _ resource.SchemaReq
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				Required: true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
 			},
 
 			"instance": schema.StringAttribute{
@@ -68,6 +69,14 @@ func (r InstanceSnapshotResource) Schema(_ context.Context, _ resource.SchemaReq
 				},
 			},
 
+			// LXD does not store snapshot descriptions, therefore the
+			// description is kept only in the Terraform state.
+			"description": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(""),
+			},
+
 			"stateful": schema.BoolAttribute{
 				Optional: true,
 				Computed: true,
@@ -77,6 +86,26 @@ func (r InstanceSnapshotResource) Schema(_ context.Context, _ resource.SchemaReq
 				},
 			},
 
+			"expires_at": schema.StringAttribute{
+				Optional:    true,
+				Description: "Time (RFC 3339) when the snapshot expires and is removed",
+				Validators: []validator.String{
+					rfc3339Validator{},
+				},
+			},
+
+			"restore_instance_on_create": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether to restore the instance from the snapshot once it is created",
+				Default:     booldefault.StaticBool(false),
+			},
+
+			"restore": schema.StringAttribute{
+				Optional:    true,
+				Description: "Arbitrary value that, when changed, restores the instance from the snapshot",
+			},
+
 			"project": schema.StringAttribute{
 				Optional: true,
 				Computed: true,
@@ -143,6 +172,12 @@ func (r InstanceSnapshotResource) Create(ctx context.Context, req resource.Creat
 		Stateful: plan.Stateful.ValueBool(),
 	}
 
+	if plan.ExpiresAt.ValueString() != "" {
+		// Format is already validated.
+		expiresAt, _ := time.Parse(time.RFC3339, plan.ExpiresAt.ValueString())
+		snapshotReq.ExpiresAt = &expiresAt
+	}
+
 	var serr error
 	for i := range 5 {
 		op, err := server.CreateInstanceSnapshot(instanceName, snapshotReq)
@@ -179,6 +214,14 @@ func (r InstanceSnapshotResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
+	if plan.RestoreOnCreate.ValueBool() {
+		err := restoreInstanceSnapshot(ctx, server, instanceName, snapshotName, plan.Stateful.ValueBool())
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to restore instance %q from snapshot %q", instanceName, snapshotName), err.Error())
+			return
+		}
+	}
+
 	// Update Terraform state.
 	diags = r.SyncState(ctx, &resp.State, server, plan, false)
 	resp.Diagnostics.Append(diags...)
@@ -208,6 +251,80 @@ func (r InstanceSnapshotResource) Read(ctx context.Context, req resource.ReadReq
 }
 
 func (r InstanceSnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan InstanceSnapshotModel
+	var state InstanceSnapshotModel
+
+	// Fetch resource model from Terraform plan and state.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := plan.Instance.ValueString()
+	snapshotName := state.Name.ValueString()
+	newSnapshotName := plan.Name.ValueString()
+
+	// Rename the snapshot.
+	if snapshotName != newSnapshotName {
+		op, err := server.RenameInstanceSnapshot(instanceName, snapshotName, api.InstanceSnapshotPost{Name: newSnapshotName})
+		if err == nil {
+			err = op.WaitContext(ctx)
+		}
+
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to rename snapshot %q for instance %q", snapshotName, instanceName), err.Error())
+			return
+		}
+
+		snapshotName = newSnapshotName
+	}
+
+	// Update the snapshot expiry.
+	if !plan.ExpiresAt.Equal(state.ExpiresAt) {
+		_, etag, err := server.GetInstanceSnapshot(instanceName, snapshotName)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve snapshot %q for instance %q", snapshotName, instanceName), err.Error())
+			return
+		}
+
+		// Zero time means the snapshot never expires.
+		snapshotPut := api.InstanceSnapshotPut{}
+		if plan.ExpiresAt.ValueString() != "" {
+			snapshotPut.ExpiresAt, _ = time.Parse(time.RFC3339, plan.ExpiresAt.ValueString())
+		}
+
+		op, err := server.UpdateInstanceSnapshot(instanceName, snapshotName, snapshotPut, etag)
+		if err == nil {
+			err = op.WaitContext(ctx)
+		}
+
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to update snapshot %q for instance %q", snapshotName, instanceName), err.Error())
+			return
+		}
+	}
+
+	// Restore the instance if the restore trigger has changed.
+	if !plan.Restore.IsNull() && !plan.Restore.Equal(state.Restore) {
+		err := restoreInstanceSnapshot(ctx, server, instanceName, snapshotName, plan.Stateful.ValueBool())
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to restore instance %q from snapshot %q", instanceName, snapshotName), err.Error())
+			return
+		}
+	}
+
+	// Update Terraform state.
+	diags := r.SyncState(ctx, &resp.State, server, plan, false)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r InstanceSnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -275,5 +392,35 @@ func (r InstanceSnapshotResource) SyncState(ctx context.Context, tfState *tfsdk.
 	m.Stateful = types.BoolValue(snapshot.Stateful)
 	m.CreatedAt = types.Int64Value(snapshot.CreatedAt.Unix())
 
+	// Snapshot expiry may be set by the instance configuration
+	// ("snapshots.expiry"), therefore it is tracked only if it
+	// is configured.
+	if m.ExpiresAt.ValueString() != "" {
+		expiresAt, err := time.Parse(time.RFC3339, m.ExpiresAt.ValueString())
+		if err != nil || !expiresAt.Equal(snapshot.ExpiresAt) {
+			m.ExpiresAt = types.StringValue(snapshot.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+	}
+
 	return tfState.Set(ctx, &m)
 }
+
+// restoreInstanceSnapshot restores the instance from the given snapshot.
+// Stateful snapshots restore the runtime state of the instance as well.
+func restoreInstanceSnapshot(ctx context.Context, server lxd.InstanceServer, instanceName string, snapshotName string, stateful bool) error {
+	instance, etag, err := server.GetInstance(instanceName)
+	if err != nil {
+		return err
+	}
+
+	instancePut := instance.Writable()
+	instancePut.Restore = snapshotName
+	instancePut.Stateful = stateful
+
+	op, err := server.UpdateInstance(instanceName, instancePut, etag)
+	if err != nil {
+		return err
+	}
+
+	return op.WaitContext(ctx)
+}
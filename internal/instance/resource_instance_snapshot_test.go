@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
 )
 
@@ -133,6 +134,80 @@ func TestAccInstanceSnapshot_missingInstance(t *testing.T) {
 	})
 }
 
+func TestAccInstanceSnapshot_update(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	snapshotName := acctest.GenerateName(2, "-")
+	newSnapshotName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceSnapshot_update(instanceName, snapshotName, "Initial", "2099-01-01T00:00:00Z"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_snapshot.snapshot1", "name", snapshotName),
+					resource.TestCheckResourceAttr("lxd_instance_snapshot.snapshot1", "description", "Initial"),
+					resource.TestCheckResourceAttr("lxd_instance_snapshot.snapshot1", "expires_at", "2099-01-01T00:00:00Z"),
+				),
+			},
+			{
+				// Rename the snapshot and change its expiry in place.
+				Config: acctest.Provider() + testAccInstanceSnapshot_update(instanceName, newSnapshotName, "Renamed", "2098-06-01T12:00:00Z"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_instance_snapshot.snapshot1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_snapshot.snapshot1", "name", newSnapshotName),
+					resource.TestCheckResourceAttr("lxd_instance_snapshot.snapshot1", "description", "Renamed"),
+					resource.TestCheckResourceAttr("lxd_instance_snapshot.snapshot1", "expires_at", "2098-06-01T12:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceSnapshot_restore(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	snapshotName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceSnapshot_restore(instanceName, snapshotName, "1", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_snapshot.snapshot1", "restore", "1"),
+				),
+			},
+			{
+				// Modify the file after the snapshot is taken.
+				Config: acctest.Provider() + testAccInstanceSnapshot_restore(instanceName, snapshotName, "1", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.modify", "create.exit_code", "0"),
+				),
+			},
+			{
+				// Changing the trigger restores the instance.
+				Config: acctest.Provider() + testAccInstanceSnapshot_restore(instanceName, snapshotName, "2", true) + `
+data "lxd_instance_file" "file" {
+  instance   = lxd_instance_snapshot.snapshot1.instance
+  path       = "/root/state.txt"
+  depends_on = [lxd_instance_snapshot.snapshot1]
+}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_snapshot.snapshot1", "restore", "2"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "content", "original\n"),
+				),
+			},
+		},
+	})
+}
+
 func testAccInstanceSnapshot_basic(instanceName, snapshotName string, stateful bool) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -217,3 +292,57 @@ resource "lxd_instance_snapshot" "snapshot1" {
 }
 	`, instanceName, snapshotName)
 }
+
+func testAccInstanceSnapshot_update(instanceName, snapshotName, description, expiresAt string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_snapshot" "snapshot1" {
+  instance    = lxd_instance.instance1.name
+  name        = "%s"
+  description = "%s"
+  expires_at  = "%s"
+}
+	`, instanceName, acctest.TestImage, snapshotName, description, expiresAt)
+}
+
+func testAccInstanceSnapshot_restore(instanceName, snapshotName, restore string, modify bool) string {
+	config := fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_exec" "init" {
+  instance = lxd_instance.instance1.name
+
+  create {
+    command = ["/bin/sh", "-c", "echo original > /root/state.txt"]
+  }
+}
+
+resource "lxd_instance_snapshot" "snapshot1" {
+  instance   = lxd_instance.instance1.name
+  name       = "%s"
+  restore    = "%s"
+  depends_on = [lxd_instance_exec.init]
+}
+	`, instanceName, acctest.TestImage, snapshotName, restore)
+
+	if modify {
+		config += `
+resource "lxd_instance_exec" "modify" {
+  instance = lxd_instance_snapshot.snapshot1.instance
+
+  create {
+    command = ["/bin/sh", "-c", "echo modified > /root/state.txt"]
+  }
+}
+		`
+	}
+
+	return config
+}
@@ -2,7 +2,9 @@ package instance_test
 
 import (
 	"fmt"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -412,6 +414,35 @@ func TestAccInstance_updateConfig(t *testing.T) {
 	})
 }
 
+func TestAccInstance_configInvalidKey(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.PreCheckAPIExtensions(t, "metadata_configuration")
+		},
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_configKey(instanceName, "limit.memory", "1GiB"),
+				ExpectError: regexp.MustCompile(`Did you mean "limits.memory"\?`),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_configKey(instanceName, "security.nesting", "maybe"),
+				ExpectError: regexp.MustCompile(`must be a boolean`),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_configKey(instanceName, "user.limit.memory", "1GiB"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.user.limit.memory", "1GiB"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_addProfile(t *testing.T) {
 	profileName := acctest.GenerateName(2, "-")
 	instanceName := acctest.GenerateName(2, "-")
@@ -505,6 +536,34 @@ func TestAccInstance_noProfile(t *testing.T) {
 	})
 }
 
+func TestAccInstance_expandedConfig(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	profileName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_expandedConfig(profileName, instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.%", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.limits.cpu", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "expanded_config.limits.cpu", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "expanded_config.limits.memory", "256MiB"),
+					resource.TestCheckNoResourceAttr("lxd_instance.instance1", "expanded_config.volatile.base_image"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "device.#", "0"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "expanded_devices.root.type", "disk"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "expanded_devices.root.properties.path", "/"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "expanded_devices.shared.type", "disk"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "expanded_devices.shared.properties.source", "/tmp"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_device(t *testing.T) {
 	instanceName := acctest.GenerateName(2, "-")
 
@@ -602,6 +661,124 @@ func TestAccInstance_removeDevice(t *testing.T) {
 	})
 }
 
+func TestAccInstance_deviceInvalidProperty(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.PreCheckAPIExtensions(t, "metadata_configuration")
+		},
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.ProviderWithConfigValidation("error") + testAccInstance_deviceProperties(instanceName, `{ path = "/tmp/shared", sourc = "/tmp" }`),
+				ExpectError: regexp.MustCompile(`(?s)Device "shared" of type "disk" requires property "source".*Did you mean "source"\?`),
+			},
+			{
+				Config:      acctest.ProviderWithConfigValidation("error") + testAccInstance_deviceProperties(instanceName, `{ source = "/tmp" }`),
+				ExpectError: regexp.MustCompile(`requires property "path"`),
+			},
+			{
+				Config:      acctest.ProviderWithConfigValidation("error") + testAccInstance_deviceProperties(instanceName, `{ path = "/tmp/shared", source = "/tmp", readonly = "maybe" }`),
+				ExpectError: regexp.MustCompile(`must be a boolean`),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_deviceProperties(instanceName, `{ path = "/tmp/shared", source = "/tmp", readonly = "true" }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "device.0.properties.readonly", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_unmanagedDevices(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_unmanagedDevices(instanceName, "delete"),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_unmanagedDevices(instanceName, "warn"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "unmanaged_devices", "warn"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "device.#", "1"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_unmanagedDevices(instanceName, "ignore"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "unmanaged_devices", "ignore"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "device.#", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "device.0.name", "shared"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_unmanagedDevices(instanceName, "adopt"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "unmanaged_devices", "adopt"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "device.#", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "device.0.name", "shared"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_labels(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_labelInConfig(instanceName),
+				ExpectError: regexp.MustCompile(`Move it into the "labels" attribute`),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_labels(instanceName, "web"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "labels.%", "2"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "labels.role", "web"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "labels.env", "prod"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.%", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.user.foo", "bar"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_labels(instanceName, "db"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "labels.%", "2"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "labels.role", "db"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.%", "1"),
+				),
+			},
+			{
+				// Remove labels.
+				Config: acctest.Provider() + testAccInstance_device_1(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckNoResourceAttr("lxd_instance.instance1", "labels"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.%", "0"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_fileUploadContainer(t *testing.T) {
 	instanceName := acctest.GenerateName(2, "-")
 
@@ -701,6 +878,198 @@ func TestAccInstance_fileUploadContent(t *testing.T) {
 	})
 }
 
+func TestAccInstance_fileUploadDir(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_fileUploadDir(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.#", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.0.source_dir", "../acctest/fixtures/test-dir"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.0.target_path", "/opt/app"),
+					resource.TestCheckResourceAttrSet("lxd_instance.instance1", "file.0.sha256"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.check", "create.stdout", "Hello from a!\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_fileContentDrift(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_fileUploadContent_1(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.#", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.0.sha256", "c98c24b677eff44860afea6f493bbaec5bb1c4cbb209c6fc2bbb47f66ff2ad31"),
+				),
+			},
+			{
+				// Modify the file within the instance, which
+				// must be detected as a drift.
+				Config:             acctest.Provider() + testAccInstance_fileContentDrift(instanceName),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Ensure the file is re-uploaded.
+				Config: acctest.Provider() + testAccInstance_fileContentDrift(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.#", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.0.sha256", "c98c24b677eff44860afea6f493bbaec5bb1c4cbb209c6fc2bbb47f66ff2ad31"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_fileTemplate(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_fileTemplate(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.#", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.0.template", "true"),
+					resource.TestCheckResourceAttrSet("lxd_instance.instance1", "file.0.sha256"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.check", "create.stdout", fmt.Sprintf("hostname: %s\n", instanceName)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_sourceBackup(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	restoredName := acctest.GenerateName(2, "-")
+	backupPath := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_sourceBackupExport(instanceName, backupPath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("lxd_instance_backup.backup1", "sha256"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_sourceBackupExport(instanceName, backupPath) + testAccInstance_sourceBackup(restoredName, backupPath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.restored", "name", restoredName),
+					resource.TestCheckResourceAttr("lxd_instance.restored", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.restored", "source_backup.path", backupPath),
+					resource.TestCheckResourceAttr("lxd_instance.restored", "config.%", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.restored", "config.user.role", "restored"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.check", "create.stdout", "Hello from backup!\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_sourceBackupNameOverride(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	restoredName := acctest.GenerateName(2, "-")
+	backupPath := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_sourceBackupExport(instanceName, backupPath),
+			},
+			{
+				// Remove the original instance, while the backup
+				// file is kept.
+				Config: acctest.Provider(),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_sourceBackupNameOverride(restoredName, backupPath),
+				ExpectError: regexp.MustCompile(`contains instance`),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_sourceBackupNameOverride(instanceName, backupPath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.restored", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.restored", "source_backup.name_override", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_sourceBackupTypeMismatch(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	restoredName := acctest.GenerateName(2, "-")
+	backupPath := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_sourceBackupExport(instanceName, backupPath),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_sourceBackupExport(instanceName, backupPath) + testAccInstance_sourceBackupType(restoredName, backupPath, "virtual-machine"),
+				ExpectError: regexp.MustCompile(`contains an instance of type "container"`),
+			},
+			{
+				// The instance restored in the previous step was
+				// removed, therefore it can be restored again.
+				Config: acctest.Provider() + testAccInstance_sourceBackupExport(instanceName, backupPath) + testAccInstance_sourceBackupType(restoredName, backupPath, "container"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.restored", "name", restoredName),
+					resource.TestCheckResourceAttr("lxd_instance.restored", "type", "container"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_sourceBackupConflictsWithImage(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  source_backup {
+    path = "/tmp/backup.tar.gz"
+  }
+}
+				`, instanceName, acctest.TestImage),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
+
 func TestAccInstance_execOutput(t *testing.T) {
 	instanceName := acctest.GenerateName(2, "-")
 
@@ -1118,6 +1487,113 @@ func TestAccInstance_execError(t *testing.T) {
 	})
 }
 
+func TestAccInstance_execStdin(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_execStdin(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.%", "2"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.content.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.content.stdout", "hello world"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.file.exit_code", "0"),
+					resource.TestCheckResourceAttrSet("lxd_instance.instance1", "execs.file.stdout"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_execOutputLimits(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_execOutputLimits(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.%", "2"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.limited.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.limited.stdout", "hello"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.sensitive.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.sensitive.stdout", ""),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.sensitive.sensitive_stdout", "secret\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_execRetries(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Command fails on the first run and succeeds on retry.
+				Config: acctest.Provider() + testAccInstance_execRetries(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.%", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.run_count", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_execTimeout(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_execTimeout(instanceName),
+				ExpectError: regexp.MustCompile("Command timed out after 2s"),
+			},
+		},
+	})
+}
+
+func TestAccInstance_execTriggerOnDestroy(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Ensure "on_destroy" command is not executed on create.
+				Config: acctest.Provider() + testAccInstance_execTriggerOnDestroy(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.%", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.trigger", "on_destroy"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.exit_code", "-1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.run_count", "0"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_configLimits(t *testing.T) {
 	instanceName := acctest.GenerateName(2, "-")
 
@@ -1309,6 +1785,90 @@ func TestAccInstance_target(t *testing.T) {
 	})
 }
 
+func TestAccInstance_access(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	key1 := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAccessTestKeyOne user1@example"
+	key2 := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAccessTestKeyTwo user2@example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_access(instanceName, true, key1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "access.user", "ops"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "access.sudo", "true"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "access.ssh_authorized_keys.#", "1"),
+					resource.TestCheckResourceAttrSet("lxd_instance.instance1", "access.method"),
+					resource.TestCheckNoResourceAttr("lxd_instance.instance1", "config.cloud-init.vendor-data"),
+					resource.TestMatchResourceAttr("data.lxd_instance_file.keys", "content", regexp.MustCompile(regexp.QuoteMeta(key1))),
+				),
+			},
+			{
+				// Changed keys are provisioned using exec and file operations.
+				Config: acctest.Provider() + testAccInstance_access(instanceName, false, key1, key2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "access.sudo", "false"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "access.ssh_authorized_keys.#", "2"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "access.method", "exec"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.keys", "content", key1+"\n"+key2+"\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_accessProfileVendorData(t *testing.T) {
+	profileName := acctest.GenerateName(2, "-")
+	instanceName := acctest.GenerateName(2, "-")
+	key := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAccessTestKeyOne user1@example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.PreCheckAPIExtensions(t, "cloud_init")
+		},
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Vendor data of the profile is not overridden, therefore
+				// the user is provisioned using exec and file operations.
+				Config: acctest.Provider() + testAccInstance_accessProfileVendorData(profileName, instanceName, key),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "access.method", "exec"),
+					resource.TestCheckNoResourceAttr("lxd_instance.instance1", "config.cloud-init.vendor-data"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.keys", "content", key+"\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_placement(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	targets := acctest.PreCheckClustering(t, 2)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_placement(instanceName, targets[0], targets[1]),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "location", targets[0]),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "placement.member", targets[0]),
+					resource.TestCheckResourceAttrSet("lxd_instance.instance1", "placement.reason"),
+					resource.TestCheckNoResourceAttr("lxd_instance.instance1", "target"),
+					// Anti-affinity places the second instance on the other member.
+					resource.TestCheckResourceAttr("lxd_instance.instance2", "location", targets[1]),
+					resource.TestCheckResourceAttr("lxd_instance.instance2", "placement.member", targets[1]),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_createProject(t *testing.T) {
 	instanceName := acctest.GenerateName(2, "-")
 	projectName := acctest.GenerateName(2, "-")
@@ -1656,6 +2216,93 @@ func TestAccInstance_waitForIPv4AndIPv6(t *testing.T) {
 	})
 }
 
+func TestAccInstance_waitForExec(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_waitForExec(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.type", "exec"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.command.#", "3"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.interval", "1s"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.timeout", "1m"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForFile(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_waitForFile(instanceName, "/etc/hostname", "1m"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.type", "file"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.path", "/etc/hostname"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForFileTimeout(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForFile(instanceName, "/non-existing", "5s"),
+				ExpectError: regexp.MustCompile(`Failed to wait for path "/non-existing" to exist`),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForPortTimeout(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForPort(instanceName, 8080, "5s"),
+				ExpectError: regexp.MustCompile("Failed to wait for TCP port 8080 to be listening"),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForInvalidProbe(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForInvalidProbe(instanceName),
+				ExpectError: regexp.MustCompile(`The "command" attribute is required when wait_for type is "exec"`),
+			},
+		},
+	})
+}
+
 func testAccInstance_basic(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -1787,6 +2434,18 @@ resource "lxd_instance" "instance1" {
 	`, name)
 }
 
+func testAccInstance_configKey(name string, key string, value string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  running = false
+  config = {
+    %q = %q
+  }
+}
+	`, name, key, value)
+}
+
 func testAccInstance_updateConfig1(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -1902,6 +2561,103 @@ resource "lxd_instance" "instance1" {
 	`, name)
 }
 
+func testAccInstance_expandedConfig(profileName string, instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_profile" "profile1" {
+  name = "%[1]s"
+
+  config = {
+    "limits.memory" = "256MiB"
+  }
+
+  device {
+    name = "shared"
+    type = "disk"
+    properties = {
+      source = "/tmp"
+      path   = "/tmp/shared"
+    }
+  }
+}
+
+resource "lxd_instance" "instance1" {
+  name     = "%[2]s"
+  image    = "%[3]s"
+  running  = false
+  profiles = ["default", lxd_profile.profile1.name]
+
+  config = {
+    "limits.cpu" = "1"
+  }
+}
+	`, profileName, instanceName, acctest.TestImage)
+}
+
+func testAccInstance_deviceProperties(name string, properties string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  running = false
+
+  device {
+    name       = "shared"
+    type       = "disk"
+    properties = %s
+  }
+}
+	`, name, properties)
+}
+
+func testAccInstance_unmanagedDevices(name string, policy string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name              = "%s"
+  running           = false
+  unmanaged_devices = "%s"
+
+  device {
+    name = "shared"
+    type = "disk"
+    properties = {
+      source = "/tmp"
+      path   = "/tmp/shared"
+    }
+  }
+}
+	`, name, policy)
+}
+
+func testAccInstance_labelInConfig(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  running = false
+
+  config = {
+    "user.label.role" = "web"
+  }
+}
+	`, name)
+}
+
+func testAccInstance_labels(name string, role string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  running = false
+
+  config = {
+    "user.foo" = "bar"
+  }
+
+  labels = {
+    "role" = "%s"
+    "env"  = "prod"
+  }
+}
+	`, name, role)
+}
+
 func testAccInstance_device_1(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -1962,37 +2718,110 @@ resource "lxd_instance" "instance1" {
     }
   }
 }
-	`, name)
+	`, name)
+}
+
+func testAccInstance_removeDevice_1(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  running = false
+
+  device {
+    name = "shared"
+    type = "disk"
+    properties = {
+      source = "/tmp"
+      path   = "/tmp/shared"
+    }
+  }
+}
+	`, name)
+}
+
+func testAccInstance_removeDevice_2(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  running = false
+}
+	`, name)
+}
+
+func testAccInstance_fileUploadContent_1(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  file {
+    content            = "Hello, World!\n"
+    target_path        = "/foo/bar.txt"
+    mode               = "0644"
+    create_directories = true
+  }
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstance_fileUploadContent_2(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  file {
+    content            = "Hello, World!\n"
+    target_path        = "/foo/bar.txt"
+    mode               = "0777"
+    create_directories = true
+  }
+}
+	`, name, acctest.TestImage)
 }
 
-func testAccInstance_removeDevice_1(name string) string {
+func testAccInstance_fileUploadContent_3(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
-  name    = "%s"
-  running = false
+  name  = "%s"
+  image = "%s"
 
-  device {
-    name = "shared"
-    type = "disk"
-    properties = {
-      source = "/tmp"
-      path   = "/tmp/shared"
-    }
+  file {
+    content            = "Goodbye, World!\n"
+    target_path        = "/foo/bar.txt"
+    mode               = "0777"
+    create_directories = false
   }
 }
-	`, name)
+	`, name, acctest.TestImage)
 }
 
-func testAccInstance_removeDevice_2(name string) string {
+func testAccInstance_fileUploadDir(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
-  name    = "%s"
-  running = false
+  name  = "%s"
+  image = "%s"
+
+  file {
+    source_dir         = "../acctest/fixtures/test-dir"
+    target_path        = "/opt/app"
+    exclude            = ["*.log"]
+    create_directories = true
+  }
 }
-	`, name)
+
+resource "lxd_instance_exec" "check" {
+  instance = lxd_instance.instance1.name
+
+  create {
+    command       = ["/bin/sh", "-c", "test -e /opt/app/debug.log || cat /opt/app/link"]
+    record_output = true
+  }
+}
+	`, name, acctest.TestImage)
 }
 
-func testAccInstance_fileUploadContent_1(name string) string {
+func testAccInstance_fileContentDrift(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
   name  = "%s"
@@ -2005,39 +2834,118 @@ resource "lxd_instance" "instance1" {
     create_directories = true
   }
 }
+
+resource "lxd_instance_exec" "modify" {
+  instance = lxd_instance.instance1.name
+
+  create {
+    command = ["/bin/sh", "-c", "echo modified > /foo/bar.txt"]
+  }
+}
 	`, name, acctest.TestImage)
 }
 
-func testAccInstance_fileUploadContent_2(name string) string {
+func testAccInstance_fileTemplate(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
   name  = "%s"
   image = "%s"
 
   file {
-    content            = "Hello, World!\n"
-    target_path        = "/foo/bar.txt"
-    mode               = "0777"
+    content            = "hostname: {{ .name }}\n"
+    target_path        = "/etc/app.yaml"
+    template           = true
     create_directories = true
   }
 }
+
+resource "lxd_instance_exec" "check" {
+  instance = lxd_instance.instance1.name
+
+  create {
+    command       = ["cat", "/etc/app.yaml"]
+    record_output = true
+  }
+}
 	`, name, acctest.TestImage)
 }
 
-func testAccInstance_fileUploadContent_3(name string) string {
+func testAccInstance_sourceBackupExport(name string, backupPath string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
   name  = "%s"
   image = "%s"
 
+  config = {
+    "user.role" = "original"
+  }
+
   file {
-    content            = "Goodbye, World!\n"
-    target_path        = "/foo/bar.txt"
-    mode               = "0777"
-    create_directories = false
+    content     = "Hello from backup!\n"
+    target_path = "/root/hello.txt"
   }
 }
-	`, name, acctest.TestImage)
+
+resource "lxd_instance_backup" "backup1" {
+  name          = "backup1"
+  instance      = lxd_instance.instance1.name
+  instance_only = true
+  output_path   = "%s"
+}
+	`, name, acctest.TestImage, backupPath)
+}
+
+func testAccInstance_sourceBackup(name string, backupPath string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "restored" {
+  name = "%s"
+
+  config = {
+    "user.role" = "restored"
+  }
+
+  source_backup {
+    path = "%s"
+  }
+}
+
+resource "lxd_instance_exec" "check" {
+  instance = lxd_instance.restored.name
+
+  create {
+    command       = ["cat", "/root/hello.txt"]
+    record_output = true
+  }
+}
+	`, name, backupPath)
+}
+
+func testAccInstance_sourceBackupNameOverride(name string, backupPath string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "restored" {
+  name    = "%s"
+  running = false
+
+  source_backup {
+    path          = "%s"
+    name_override = false
+  }
+}
+	`, name, backupPath)
+}
+
+func testAccInstance_sourceBackupType(name string, backupPath string, instanceType string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "restored" {
+  name    = "%s"
+  type    = "%s"
+  running = false
+
+  source_backup {
+    path = "%s"
+  }
+}
+	`, name, instanceType, backupPath)
 }
 
 func testAccInstance_fileUploadSource(instanceName string, instanceType string) string {
@@ -2769,3 +3677,251 @@ resource "lxd_instance" "instance1" {
 }
 	`, networkName, subnet.GatewayCIDRv4(), subnet.GatewayCIDRv6(), instanceName, acctest.TestImage, subnet.HostIPv4(200))
 }
+
+func testAccInstance_waitForExec(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  wait_for {
+    type     = "exec"
+    command  = ["test", "-e", "/etc/os-release"]
+    interval = "1s"
+    timeout  = "1m"
+  }
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstance_waitForFile(name string, path string, timeout string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  wait_for {
+    type    = "file"
+    path    = "%s"
+    timeout = "%s"
+  }
+}
+	`, name, acctest.TestImage, path, timeout)
+}
+
+func testAccInstance_waitForPort(name string, port int, timeout string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  wait_for {
+    type     = "port"
+    port     = %d
+    interval = "1s"
+    timeout  = "%s"
+  }
+}
+	`, name, acctest.TestImage, port, timeout)
+}
+
+func testAccInstance_waitForInvalidProbe(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  wait_for {
+    type = "exec"
+  }
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstance_execStdin(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  execs = {
+    "content" = {
+      command       = ["cat"]
+      stdin         = "hello world"
+      record_output = true
+      fail_on_error = true
+    }
+
+    "file" = {
+      command       = ["cat"]
+      stdin_file    = "../acctest/fixtures/test-file.txt"
+      record_output = true
+      fail_on_error = true
+    }
+  }
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstance_execOutputLimits(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  execs = {
+    "limited" = {
+      command          = ["echo", "hello world"]
+      record_output    = true
+      max_output_bytes = 5
+    }
+
+    "sensitive" = {
+      command          = ["echo", "secret"]
+      record_output    = true
+      sensitive_output = true
+    }
+  }
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstance_execRetries(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  execs = {
+    "cmd" = {
+      # Fails until the marker file is created by the first attempt.
+      command        = ["/bin/sh", "-c", "test -e /tmp/retry || { touch /tmp/retry; exit 1; }"]
+      retries        = 2
+      retry_interval = "1s"
+      fail_on_error  = true
+    }
+  }
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstance_execTimeout(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  execs = {
+    "cmd" = {
+      command       = ["sleep", "60"]
+      timeout       = "2s"
+      fail_on_error = true
+    }
+  }
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstance_execTriggerOnDestroy(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  execs = {
+    "cmd" = {
+      command       = ["/bin/sh", "-c", "echo deregistered"]
+      trigger       = "on_destroy"
+      fail_on_error = true
+    }
+  }
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstance_placement(name string, member1 string, member2 string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%[1]s-1"
+  image = "%[2]s"
+
+  labels = {
+    role = "web"
+  }
+
+  placement {
+    spread_across       = ["%[3]s"]
+    anti_affinity_label = "role"
+  }
+}
+
+resource "lxd_instance" "instance2" {
+  name  = "%[1]s-2"
+  image = "%[2]s"
+
+  labels = {
+    role = "web"
+  }
+
+  placement {
+    spread_across       = ["%[3]s", "%[4]s"]
+    prefer_members      = ["%[3]s"]
+    anti_affinity_label = "role"
+  }
+
+  depends_on = [lxd_instance.instance1]
+}
+	`, name, acctest.TestImage, member1, member2)
+}
+
+func testAccInstance_access(name string, sudo bool, keys ...string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  access {
+    user                = "ops"
+    ssh_authorized_keys = ["%s"]
+    sudo                = %t
+  }
+}
+
+data "lxd_instance_file" "keys" {
+  instance = lxd_instance.instance1.name
+  path     = "/home/ops/.ssh/authorized_keys"
+
+  depends_on = [lxd_instance.instance1]
+}
+	`, name, acctest.TestImage, strings.Join(keys, `","`), sudo)
+}
+
+func testAccInstance_accessProfileVendorData(profileName string, instanceName string, key string) string {
+	return fmt.Sprintf(`
+resource "lxd_profile" "profile1" {
+  name = "%s"
+
+  config = {
+    "cloud-init.vendor-data" = "#cloud-config\npackage_update: false\n"
+  }
+}
+
+resource "lxd_instance" "instance1" {
+  name     = "%s"
+  image    = "%s"
+  profiles = ["default", lxd_profile.profile1.name]
+
+  access {
+    user                = "ops"
+    ssh_authorized_keys = ["%s"]
+  }
+}
+
+data "lxd_instance_file" "keys" {
+  instance = lxd_instance.instance1.name
+  path     = "/home/ops/.ssh/authorized_keys"
+
+  depends_on = [lxd_instance.instance1]
+}
+	`, profileName, instanceName, acctest.TestImage, key)
+}
@@ -3,6 +3,8 @@ package instance
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
@@ -28,3 +30,99 @@ func (v configKeyValidator) ValidateString(ctx context.Context, req validator.St
 		)
 	}
 }
+
+// durationValidator ensures value is a valid duration (e.g. "30s"). If
+// positive is true, the duration must also be greater than zero.
+type durationValidator struct {
+	positive bool
+}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	if v.positive {
+		return "value must be a valid positive duration, such as \"30s\" or \"5m\""
+	}
+
+	return "value must be a valid duration, such as \"30s\" or \"5m\""
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	if v.positive {
+		return "value must be a valid positive duration, such as `30s` or `5m`"
+	}
+
+	return "value must be a valid duration, such as `30s` or `5m`"
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration",
+			fmt.Sprintf("Value %q is not a valid duration: %v.", value, err),
+		)
+
+		return
+	}
+
+	if v.positive && duration <= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration",
+			fmt.Sprintf("Duration must be greater than zero. Got: %q.", value),
+		)
+	}
+}
+
+// absolutePathValidator ensures value is an absolute path.
+type absolutePathValidator struct{}
+
+func (v absolutePathValidator) Description(ctx context.Context) string {
+	return "value must be an absolute path"
+}
+
+func (v absolutePathValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be an absolute path"
+}
+
+func (v absolutePathValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+
+	if !strings.HasPrefix(value, "/") {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid path",
+			fmt.Sprintf("Path must be absolute. Got: %q.", value),
+		)
+	}
+}
+
+// rfc3339Validator ensures value is a valid RFC 3339 timestamp.
+type rfc3339Validator struct{}
+
+func (v rfc3339Validator) Description(ctx context.Context) string {
+	return "value must be a valid RFC 3339 timestamp, such as \"2030-01-01T00:00:00Z\""
+}
+
+func (v rfc3339Validator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a valid RFC 3339 timestamp, such as `2030-01-01T00:00:00Z`"
+}
+
+func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+
+	_, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid timestamp",
+			fmt.Sprintf("Value %q is not a valid RFC 3339 timestamp: %v.", value, err),
+		)
+	}
+}
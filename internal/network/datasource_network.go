@@ -23,6 +23,7 @@ type NetworkDataSourceModel struct {
 	Type        types.String `tfsdk:"type"`
 	Managed     types.Bool   `tfsdk:"managed"`
 	Config      types.Map    `tfsdk:"config"`
+	Labels      types.Map    `tfsdk:"labels"`
 	IPv4        types.String `tfsdk:"ipv4_address"`
 	IPv6        types.String `tfsdk:"ipv6_address"`
 }
@@ -72,6 +73,11 @@ func (d *NetworkDataSource) Schema(_ context.Context, req datasource.SchemaReque
 				ElementType: types.StringType,
 			},
 
+			"labels": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+
 			"ipv4_address": schema.StringAttribute{
 				Computed: true,
 			},
@@ -136,12 +142,16 @@ func (d *NetworkDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	config, diags := common.ToConfigMapType(ctx, common.ToNullableConfig(network.Config), state.Config)
 	resp.Diagnostics.Append(diags...)
 
+	labels, diags := types.MapValueFrom(ctx, types.StringType, common.LabelsFromConfig(network.Config))
+	resp.Diagnostics.Append(diags...)
+
 	state.Name = types.StringValue(network.Name)
 	state.Description = types.StringValue(network.Description)
 	state.Type = types.StringValue(network.Type)
 	state.Managed = types.BoolValue(network.Managed)
 	state.Project = types.StringValue(network.Project)
 	state.Config = config
+	state.Labels = labels
 
 	state.IPv4 = types.StringValue(ipv4)
 	state.IPv6 = types.StringValue(ipv6)
@@ -9,6 +9,7 @@ import (
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -35,6 +36,7 @@ type NetworkModel struct {
 	Project         types.String `tfsdk:"project"`
 	Remote          types.String `tfsdk:"remote"`
 	Config          types.Map    `tfsdk:"config"`
+	Labels          types.Map    `tfsdk:"labels"`
 	MemberOverrides types.Map    `tfsdk:"member_overrides"`
 	Members         types.Map    `tfsdk:"members"`
 
@@ -114,6 +116,20 @@ func (r NetworkResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				Optional:    true,
 				Computed:    true,
 				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(common.NoLabelKeyValidator{}),
+				},
+			},
+
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels of the network, stored as user.label.* config keys",
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					// Prevent empty values.
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
 			},
 
 			// Contains only local (member-specific) network configuration that
@@ -260,6 +276,15 @@ func (r NetworkResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	labels, diags := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Labels are part of the cluster-wide network configuration.
+	maps.Copy(networkConfig, common.LabelsToConfig(labels))
+
 	// Create per-member network definitions.
 	for memberName, memberNetworkConfig := range memberNetworkConfigs {
 		memberServer := server.UseTarget(memberName)
@@ -377,6 +402,15 @@ func (r NetworkResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	labels, diags := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Labels are part of the cluster-wide network configuration.
+	maps.Copy(networkConfig, common.LabelsToConfig(labels))
+
 	// Update all members present in the plan.
 	for memberName, memberNetworkConfig := range memberNetworkConfigs {
 		memberServer := server.UseTarget(memberName)
@@ -435,7 +469,7 @@ func (r NetworkResource) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 
 	// Update Terraform state.
-	diags := r.SyncState(ctx, &resp.State, server, plan, false)
+	diags = r.SyncState(ctx, &resp.State, server, plan, false)
 	resp.Diagnostics.Append(diags...)
 }
 
@@ -558,12 +592,18 @@ func (r NetworkResource) SyncState(ctx context.Context, tfState *tfsdk.State, se
 
 	// Merge current network configuration with user provided configuration, stripping away
 	// computed fields that were not set by the user.
-	networkConfig := common.StripConfig(network.Config, m.Config, m.ComputedKeys())
+	// Labels are excluded from the config.
+	networkConfig := common.StripConfig(common.StripLabels(network.Config), m.Config, m.ComputedKeys())
 	configValue, diags := common.ToConfigMapType(ctx, networkConfig, m.Config)
 	if diags.HasError() {
 		return diags
 	}
 
+	labels, diags := common.ToLabelsMapType(ctx, network.Config, m.Labels)
+	if diags.HasError() {
+		return diags
+	}
+
 	memberObjType := types.ObjectType{
 		AttrTypes: map[string]attr.Type{
 			"config": types.MapType{ElemType: types.StringType},
@@ -580,6 +620,7 @@ func (r NetworkResource) SyncState(ctx context.Context, tfState *tfsdk.State, se
 	m.Managed = types.BoolValue(network.Managed)
 	m.Type = types.StringValue(network.Type)
 	m.Config = configValue
+	m.Labels = labels
 	m.Members = membersValue
 
 	m.IPv4 = types.StringValue(ipv4)
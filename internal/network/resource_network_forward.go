@@ -6,6 +6,7 @@ import (
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -33,15 +34,27 @@ type NetworkForwardModel struct {
 	Project       types.String `tfsdk:"project"`
 	Remote        types.String `tfsdk:"remote"`
 	Config        types.Map    `tfsdk:"config"`
+
+	// Computed.
+	ResolvedTargets types.Map `tfsdk:"resolved_targets"`
 }
 
 // NetworkForwardPortModel resource data model that matches the schema.
 type NetworkForwardPortModel struct {
-	Description   types.String `tfsdk:"description"`
-	Protocol      types.String `tfsdk:"protocol"`
-	ListenPort    types.String `tfsdk:"listen_port"`
-	TargetPort    types.String `tfsdk:"target_port"`
-	TargetAddress types.String `tfsdk:"target_address"`
+	Description    types.String `tfsdk:"description"`
+	Protocol       types.String `tfsdk:"protocol"`
+	ListenPort     types.String `tfsdk:"listen_port"`
+	TargetPort     types.String `tfsdk:"target_port"`
+	TargetAddress  types.String `tfsdk:"target_address"`
+	TargetInstance types.String `tfsdk:"target_instance"`
+	TargetSelector types.Map    `tfsdk:"target_selector"`
+	TargetNIC      types.String `tfsdk:"target_nic"`
+}
+
+// hasInstanceTarget returns true if the port target is resolved from
+// an instance instead of using a static target address.
+func (m NetworkForwardPortModel) hasInstanceTarget() bool {
+	return m.TargetAddress.IsNull()
 }
 
 // NetworkForwardResource represent network forward resource.
@@ -135,12 +148,54 @@ func (r *NetworkForwardResource) Schema(ctx context.Context, req resource.Schema
 						},
 
 						"target_address": schema.StringAttribute{
-							Required:    true,
+							Optional:    true,
 							Description: "Target address to forward listen port to",
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(
+									path.MatchRelative().AtParent().AtName("target_instance"),
+									path.MatchRelative().AtParent().AtName("target_selector"),
+								),
+							},
+						},
+
+						"target_instance": schema.StringAttribute{
+							Optional:    true,
+							Description: "Name of the instance to forward listen port to",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+
+						"target_selector": schema.MapAttribute{
+							Optional:    true,
+							Description: "Labels of the instance to forward listen port to",
+							ElementType: types.StringType,
+							Validators: []validator.Map{
+								mapvalidator.SizeAtLeast(1),
+							},
+						},
+
+						"target_nic": schema.StringAttribute{
+							Optional:    true,
+							Description: "Instance NIC used to resolve target address. Defaults to eth0",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+								stringvalidator.ConflictsWith(
+									path.MatchRelative().AtParent().AtName("target_address"),
+								),
+							},
 						},
 					},
 				},
 			},
+
+			// Computed.
+
+			"resolved_targets": schema.MapAttribute{
+				Computed:    true,
+				Description: "Target addresses resolved from instances, keyed by <protocol>/<listen_port>",
+				ElementType: types.StringType,
+			},
 		},
 	}
 }
@@ -148,11 +203,14 @@ func (r *NetworkForwardResource) Schema(ctx context.Context, req resource.Schema
 func portObjectType() types.ObjectType {
 	return types.ObjectType{
 		AttrTypes: map[string]attr.Type{
-			"description":    types.StringType,
-			"protocol":       types.StringType,
-			"listen_port":    types.StringType,
-			"target_port":    types.StringType,
-			"target_address": types.StringType,
+			"description":     types.StringType,
+			"protocol":        types.StringType,
+			"listen_port":     types.StringType,
+			"target_port":     types.StringType,
+			"target_address":  types.StringType,
+			"target_instance": types.StringType,
+			"target_selector": types.MapType{ElemType: types.StringType},
+			"target_nic":      types.StringType,
 		},
 	}
 }
@@ -172,6 +230,48 @@ func (r *NetworkForwardResource) Configure(_ context.Context, req resource.Confi
 	r.provider = provider
 }
 
+// ModifyPlan re-evaluates the targets of ports referencing instances.
+func (r *NetworkForwardResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.provider == nil {
+		// Nothing to do on destroy.
+		return
+	}
+
+	var plan NetworkForwardModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Ports.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resolved_targets"), types.MapUnknown(types.StringType))...)
+		return
+	}
+
+	ports, diags := toNetworkForwardPortModels(ctx, plan.Ports)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !hasForwardInstanceTargets(ports) {
+		// All ports have a static target address.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resolved_targets"), types.MapNull(types.StringType))...)
+		return
+	}
+
+	var targets map[string]string
+	if !plan.Remote.IsUnknown() && !plan.Project.IsUnknown() && !plan.ListenAddress.IsUnknown() {
+		server, err := r.provider.InstanceServer(plan.Remote.ValueString(), plan.Project.ValueString(), "")
+		if err == nil {
+			family := common.AddressFamily(plan.ListenAddress.ValueString())
+			targets, _ = resolveForwardPortTargets(server, ports, family)
+		}
+	}
+
+	resp.Diagnostics.Append(planResolvedTargets(ctx, req, resp, targets)...)
+}
+
 func (r *NetworkForwardResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan NetworkForwardModel
 
@@ -189,8 +289,22 @@ func (r *NetworkForwardResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	ports, diags := ToNetworkForwardPortList(ctx, plan.Ports)
+	networkName := plan.Network.ValueString()
+	listenAddress := plan.ListenAddress.ValueString()
+
+	modelPorts, diags := toNetworkForwardPortModels(ctx, plan.Ports)
 	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	targets, err := resolveForwardPortTargets(server, modelPorts, common.AddressFamily(listenAddress))
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to resolve port targets of network forward for %q", listenAddress), err.Error())
+		return
+	}
+
+	ports := ToNetworkForwardPortList(modelPorts, targets)
 
 	config, diags := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diags...)
@@ -199,9 +313,6 @@ func (r *NetworkForwardResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	networkName := plan.Network.ValueString()
-	listenAddress := plan.ListenAddress.ValueString()
-
 	createRequest := api.NetworkForwardsPost{
 		ListenAddress: listenAddress,
 		NetworkForwardPut: api.NetworkForwardPut{
@@ -269,8 +380,22 @@ func (r *NetworkForwardResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	ports, diags := ToNetworkForwardPortList(ctx, plan.Ports)
+	networkName := plan.Network.ValueString()
+	listenAddress := plan.ListenAddress.ValueString()
+
+	modelPorts, diags := toNetworkForwardPortModels(ctx, plan.Ports)
 	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	targets, err := resolveForwardPortTargets(server, modelPorts, common.AddressFamily(listenAddress))
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to resolve port targets of network forward for %q", listenAddress), err.Error())
+		return
+	}
+
+	ports := ToNetworkForwardPortList(modelPorts, targets)
 
 	config, diags := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diags...)
@@ -279,9 +404,6 @@ func (r *NetworkForwardResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	networkName := plan.Network.ValueString()
-	listenAddress := plan.ListenAddress.ValueString()
-
 	updateRequest := api.NetworkForwardPut{
 		Description: plan.Description.ValueString(),
 		Ports:       ports,
@@ -381,7 +503,12 @@ func (r *NetworkForwardResource) SyncState(ctx context.Context, tfState *tfsdk.S
 		)}
 	}
 
-	ports, diags := ToNetworkForwardPortSetType(ctx, networkForward.Ports)
+	modelPorts, diags := toNetworkForwardPortModels(ctx, m.Ports)
+	if diags.HasError() {
+		return diags
+	}
+
+	ports, resolvedTargets, diags := ToNetworkForwardPortSetType(ctx, networkForward.Ports, modelPorts)
 	if diags.HasError() {
 		return diags
 	}
@@ -394,14 +521,16 @@ func (r *NetworkForwardResource) SyncState(ctx context.Context, tfState *tfsdk.S
 	m.Description = types.StringValue(networkForward.Description)
 	m.Ports = ports
 	m.Config = config
+	m.ResolvedTargets = resolvedTargets
 
 	return tfState.Set(ctx, &m)
 }
 
-// ToNetworkForwardPortList converts forward ports from type types.Set into []api.NetworkForwardPort.
-func ToNetworkForwardPortList(ctx context.Context, portsSet types.Set) ([]api.NetworkForwardPort, diag.Diagnostics) {
+// toNetworkForwardPortModels converts forward ports from type types.Set
+// into a list of port models.
+func toNetworkForwardPortModels(ctx context.Context, portsSet types.Set) ([]NetworkForwardPortModel, diag.Diagnostics) {
 	if portsSet.IsNull() || portsSet.IsUnknown() {
-		return []api.NetworkForwardPort{}, nil
+		return nil, nil
 	}
 
 	modelPorts := make([]NetworkForwardPortModel, 0, len(portsSet.Elements()))
@@ -410,6 +539,74 @@ func ToNetworkForwardPortList(ctx context.Context, portsSet types.Set) ([]api.Ne
 		return nil, diags
 	}
 
+	return modelPorts, nil
+}
+
+// hasForwardInstanceTargets returns true if any of the ports resolves its
+// target from an instance.
+func hasForwardInstanceTargets(ports []NetworkForwardPortModel) bool {
+	for _, p := range ports {
+		if p.hasInstanceTarget() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveForwardPortTargets resolves the addresses of instances referenced
+// by the forward ports. Each port must resolve to exactly one instance. The
+// returned map is keyed by the port key (see toForwardPortKey).
+func resolveForwardPortTargets(server lxd.InstanceServer, ports []NetworkForwardPortModel, family string) (map[string]string, error) {
+	targets := make(map[string]string)
+	for _, p := range ports {
+		if !p.hasInstanceTarget() {
+			continue
+		}
+
+		key := toForwardPortKey(p.Protocol.ValueString(), p.ListenPort.ValueString())
+		if p.TargetInstance.IsUnknown() || p.TargetSelector.IsUnknown() || p.TargetNIC.IsUnknown() {
+			return nil, fmt.Errorf("Target of port %q is not known yet", key)
+		}
+
+		var names []string
+		if !p.TargetInstance.IsNull() {
+			names = []string{p.TargetInstance.ValueString()}
+		}
+
+		selector := make(map[string]string, len(p.TargetSelector.Elements()))
+		for k, v := range p.TargetSelector.Elements() {
+			value, ok := v.(types.String)
+			if ok {
+				selector[k] = value.ValueString()
+			}
+		}
+
+		addresses, err := common.ResolveInstanceAddresses(server, names, selector, p.TargetNIC.ValueString(), family)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to resolve target of port %q: %w", key, err)
+		}
+
+		if len(addresses) != 1 {
+			return nil, fmt.Errorf("Target of port %q must resolve to exactly one instance with an address, but resolved to %d", key, len(addresses))
+		}
+
+		for _, address := range addresses {
+			targets[key] = address
+		}
+	}
+
+	return targets, nil
+}
+
+// toForwardPortKey returns a key identifying the forward port.
+func toForwardPortKey(protocol string, listenPort string) string {
+	return protocol + "/" + listenPort
+}
+
+// ToNetworkForwardPortList converts forward port models into []api.NetworkForwardPort.
+// Target addresses of ports referencing instances are taken from the resolved targets.
+func ToNetworkForwardPortList(modelPorts []NetworkForwardPortModel, targets map[string]string) []api.NetworkForwardPort {
 	ports := make([]api.NetworkForwardPort, 0, len(modelPorts))
 	for _, modelPort := range modelPorts {
 		port := api.NetworkForwardPort{
@@ -420,38 +617,71 @@ func ToNetworkForwardPortList(ctx context.Context, portsSet types.Set) ([]api.Ne
 			TargetAddress: modelPort.TargetAddress.ValueString(),
 		}
 
+		if modelPort.hasInstanceTarget() {
+			port.TargetAddress = targets[toForwardPortKey(port.Protocol, port.ListenPort)]
+		}
+
 		ports = append(ports, port)
 	}
 
-	return ports, nil
+	return ports
 }
 
 // ToNetworkForwardPortSetType converts []api.NetworkForwardPort into forward ports of type types.Set.
-func ToNetworkForwardPortSetType(ctx context.Context, ports []api.NetworkForwardPort) (types.Set, diag.Diagnostics) {
+// Target addresses of ports referencing instances are returned as resolved targets.
+func ToNetworkForwardPortSetType(ctx context.Context, ports []api.NetworkForwardPort, modelPorts []NetworkForwardPortModel) (types.Set, types.Map, diag.Diagnostics) {
 	portObjectType := portObjectType()
 	nilSet := types.SetNull(portObjectType)
 
+	resolvedTargets := types.MapNull(types.StringType)
+	if hasForwardInstanceTargets(modelPorts) {
+		resolvedTargets = types.MapValueMust(types.StringType, map[string]attr.Value{})
+	}
+
 	if len(ports) == 0 {
-		return nilSet, nil
+		return nilSet, resolvedTargets, nil
 	}
 
+	targets := make(map[string]attr.Value)
 	portList := make([]attr.Value, 0, len(ports))
 	for _, port := range ports {
 		portMap := map[string]attr.Value{
-			"description":    types.StringValue(port.Description),
-			"protocol":       types.StringValue(port.Protocol),
-			"listen_port":    types.StringValue(port.ListenPort),
-			"target_port":    types.StringValue(port.TargetPort),
-			"target_address": types.StringValue(port.TargetAddress),
+			"description":     types.StringValue(port.Description),
+			"protocol":        types.StringValue(port.Protocol),
+			"listen_port":     types.StringValue(port.ListenPort),
+			"target_port":     types.StringValue(port.TargetPort),
+			"target_address":  types.StringValue(port.TargetAddress),
+			"target_instance": types.StringNull(),
+			"target_selector": types.MapNull(types.StringType),
+			"target_nic":      types.StringNull(),
+		}
+
+		for _, modelPort := range modelPorts {
+			if !modelPort.hasInstanceTarget() || modelPort.Protocol.ValueString() != port.Protocol || modelPort.ListenPort.ValueString() != port.ListenPort {
+				continue
+			}
+
+			// Port target is resolved from an instance.
+			targets[toForwardPortKey(port.Protocol, port.ListenPort)] = types.StringValue(port.TargetAddress)
+			portMap["target_address"] = types.StringNull()
+			portMap["target_instance"] = modelPort.TargetInstance
+			portMap["target_selector"] = modelPort.TargetSelector
+			portMap["target_nic"] = modelPort.TargetNIC
+			break
 		}
 
 		portObject, diags := types.ObjectValue(portObjectType.AttrTypes, portMap)
 		if diags.HasError() {
-			return nilSet, diags
+			return nilSet, resolvedTargets, diags
 		}
 
 		portList = append(portList, portObject)
 	}
 
-	return types.SetValue(portObjectType, portList)
+	if !resolvedTargets.IsNull() {
+		resolvedTargets = types.MapValueMust(types.StringType, targets)
+	}
+
+	portSet, diags := types.SetValue(portObjectType, portList)
+	return portSet, resolvedTargets, diags
 }
@@ -72,6 +72,32 @@ func TestAccNetworkForward_Ports(t *testing.T) {
 	})
 }
 
+func TestAccNetworkForward_instanceTarget(t *testing.T) {
+	networkName := acctest.GenerateName(1, "")
+	instanceName := acctest.GenerateName(2, "-")
+	subnet := acctest.GenerateSubnet()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.PreCheckStandalone(t) // Due to standalone network creation.
+		},
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccNetworkForward_instanceTarget(networkName, instanceName, subnet),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_network_forward.forward", "ports.#", "1"),
+					resource.TestCheckResourceAttr("lxd_network_forward.forward", "ports.0.target_instance", instanceName),
+					resource.TestCheckNoResourceAttr("lxd_network_forward.forward", "ports.0.target_address"),
+					resource.TestCheckResourceAttr("lxd_network_forward.forward", "resolved_targets.%", "1"),
+					resource.TestCheckResourceAttr("lxd_network_forward.forward", "resolved_targets.tcp/80", subnet.HostIPv4(112)),
+				),
+			},
+		},
+	})
+}
+
 func testAccNetworkForward(networkName string, subnet acctest.Subnet) string {
 	return fmt.Sprintf(`
 resource "lxd_network" "forward" {
@@ -137,3 +163,47 @@ resource "lxd_network_forward" "forward" {
 }
   `, networkName, subnet.GatewayCIDRv4(), subnet.GatewayCIDRv6(), subnet.HostIPv4(10), subnet.HostIPv4(111), subnet.HostIPv4(112))
 }
+
+func testAccNetworkForward_instanceTarget(networkName string, instanceName string, subnet acctest.Subnet) string {
+	return fmt.Sprintf(`
+resource "lxd_network" "forward" {
+  name = "%[1]s"
+
+  config = {
+    "ipv4.address" = "%[2]s"
+    "ipv4.nat"     = "true"
+  }
+}
+
+resource "lxd_instance" "instance" {
+  name  = "%[3]s"
+  image = "%[4]s"
+
+  wait_for {
+    type = "ipv4"
+  }
+
+  device {
+    name = "eth0"
+    type = "nic"
+    properties = {
+      "network"      = lxd_network.forward.name
+      "ipv4.address" = "%[6]s"
+    }
+  }
+}
+
+resource "lxd_network_forward" "forward" {
+  network        = lxd_network.forward.name
+  listen_address = "%[5]s"
+
+  ports = [
+    {
+      protocol        = "tcp"
+      listen_port     = "80"
+      target_instance = lxd_instance.instance.name
+    }
+  ]
+}
+  `, networkName, subnet.GatewayCIDRv4(), instanceName, acctest.TestImage, subnet.HostIPv4(10), subnet.HostIPv4(112))
+}
@@ -3,9 +3,13 @@ package network
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -34,6 +38,9 @@ type NetworkLBModel struct {
 	Project       types.String `tfsdk:"project"`
 	Remote        types.String `tfsdk:"remote"`
 	Config        types.Map    `tfsdk:"config"`
+
+	// Computed.
+	ResolvedTargets types.Map `tfsdk:"resolved_targets"`
 }
 
 // LxdNetworkLBResource represent LXD network load balancer resource.
@@ -94,6 +101,14 @@ func (r LxdNetworkLBResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+
+			// Computed.
+
+			"resolved_targets": schema.MapAttribute{
+				Computed:    true,
+				Description: "Target addresses resolved from instances, keyed by LB backend name",
+				ElementType: types.StringType,
+			},
 		},
 
 		Blocks: map[string]schema.Block{
@@ -114,8 +129,43 @@ func (r LxdNetworkLBResource) Schema(_ context.Context, _ resource.SchemaRequest
 						},
 
 						"target_address": schema.StringAttribute{
-							Required:    true,
+							Optional:    true,
 							Description: "LB backend target address",
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(
+									path.MatchRelative().AtParent().AtName("target_instances"),
+									path.MatchRelative().AtParent().AtName("target_selector"),
+								),
+							},
+						},
+
+						"target_instances": schema.SetAttribute{
+							Optional:    true,
+							Description: "Names of instances used as LB backend targets",
+							ElementType: types.StringType,
+							Validators: []validator.Set{
+								setvalidator.SizeAtLeast(1),
+							},
+						},
+
+						"target_selector": schema.MapAttribute{
+							Optional:    true,
+							Description: "Labels of instances used as LB backend targets",
+							ElementType: types.StringType,
+							Validators: []validator.Map{
+								mapvalidator.SizeAtLeast(1),
+							},
+						},
+
+						"target_nic": schema.StringAttribute{
+							Optional:    true,
+							Description: "Instance NIC used to resolve target addresses. Defaults to eth0",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+								stringvalidator.ConflictsWith(
+									path.MatchRelative().AtParent().AtName("target_address"),
+								),
+							},
 						},
 
 						"target_port": schema.StringAttribute{
@@ -185,6 +235,55 @@ func (r *LxdNetworkLBResource) Configure(_ context.Context, req resource.Configu
 	r.provider = provider
 }
 
+// ModifyPlan checks whether the server supports network load balancers
+// and re-evaluates the targets of backends referencing instances.
+func (r LxdNetworkLBResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip the checks on destroy or if the server is not reachable.
+	server := r.provider.PlanInstanceServer(ctx, req.Plan)
+	if server == nil {
+		return
+	}
+
+	var plan NetworkLBModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(common.CheckResourceExtensions(server, "lxd_network_lb", "network_load_balancer")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Backends.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resolved_targets"), types.MapUnknown(types.StringType))...)
+		return
+	}
+
+	backends, diags := toLBBackendModels(ctx, plan.Backends)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !hasInstanceTargets(backends) {
+		// All backends have a static target address.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resolved_targets"), types.MapNull(types.StringType))...)
+		return
+	}
+
+	var targets map[lbBackendTarget]string
+	if !plan.Project.IsUnknown() && !plan.ListenAddress.IsUnknown() {
+		server, err := r.provider.InstanceServer(plan.Remote.ValueString(), plan.Project.ValueString(), "")
+		if err == nil {
+			family := common.AddressFamily(plan.ListenAddress.ValueString())
+			targets, _ = resolveLBBackendTargets(server, backends, family)
+		}
+	}
+
+	resp.Diagnostics.Append(planResolvedTargets(ctx, req, resp, lbResolvedTargets(targets))...)
+}
+
 func (r LxdNetworkLBResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan NetworkLBModel
 
@@ -202,10 +301,25 @@ func (r LxdNetworkLBResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	backends, diag := ToLBBackendList(ctx, plan.Backends)
+	networkName := plan.Network.ValueString()
+	listenAddr := plan.ListenAddress.ValueString()
+	lbName := toLBName(networkName, listenAddr)
+
+	modelBackends, diag := toLBBackendModels(ctx, plan.Backends)
 	resp.Diagnostics.Append(diag...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	ports, diag := ToLBPortList(ctx, plan.Ports)
+	targets, err := resolveLBBackendTargets(server, modelBackends, common.AddressFamily(listenAddr))
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to resolve backend targets of network load balancer %q", lbName), err.Error())
+		return
+	}
+
+	backends := ToLBBackendList(modelBackends, targets)
+
+	ports, diag := ToLBPortList(ctx, plan.Ports, modelBackends, targets)
 	resp.Diagnostics.Append(diag...)
 
 	config, diag := common.ToConfigMap(ctx, plan.Config)
@@ -215,10 +329,6 @@ func (r LxdNetworkLBResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	networkName := plan.Network.ValueString()
-	listenAddr := plan.ListenAddress.ValueString()
-	lbName := toLBName(networkName, listenAddr)
-
 	lbReq := api.NetworkLoadBalancersPost{
 		ListenAddress: listenAddr,
 		NetworkLoadBalancerPut: api.NetworkLoadBalancerPut{
@@ -290,10 +400,25 @@ func (r LxdNetworkLBResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	backends, diag := ToLBBackendList(ctx, plan.Backends)
+	networkName := plan.Network.ValueString()
+	listenAddr := plan.ListenAddress.ValueString()
+	lbName := toLBName(networkName, listenAddr)
+
+	modelBackends, diag := toLBBackendModels(ctx, plan.Backends)
 	resp.Diagnostics.Append(diag...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	targets, err := resolveLBBackendTargets(server, modelBackends, common.AddressFamily(listenAddr))
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to resolve backend targets of network load balancer %q", lbName), err.Error())
+		return
+	}
+
+	backends := ToLBBackendList(modelBackends, targets)
 
-	ports, diag := ToLBPortList(ctx, plan.Ports)
+	ports, diag := ToLBPortList(ctx, plan.Ports, modelBackends, targets)
 	resp.Diagnostics.Append(diag...)
 
 	config, diag := common.ToConfigMap(ctx, plan.Config)
@@ -303,10 +428,6 @@ func (r LxdNetworkLBResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	networkName := plan.Network.ValueString()
-	listenAddr := plan.ListenAddress.ValueString()
-	lbName := toLBName(networkName, listenAddr)
-
 	lbReq := api.NetworkLoadBalancerPut{
 		Description: plan.Description.ValueString(),
 		Backends:    backends,
@@ -399,10 +520,21 @@ func (r LxdNetworkLBResource) SyncState(ctx context.Context, tfState *tfsdk.Stat
 		return respDiags
 	}
 
-	backends, diags := ToLBBackendSetType(ctx, lb.Backends)
+	modelBackends, diags := toLBBackendModels(ctx, m.Backends)
+	respDiags.Append(diags...)
+
+	// Resolve the current targets to identify the API backends created
+	// for them. If they cannot be resolved (e.g. an instance no longer
+	// exists), the API backends are matched by name.
+	var targets map[lbBackendTarget]string
+	if hasInstanceTargets(modelBackends) {
+		targets, _ = resolveLBBackendTargets(server, modelBackends, common.AddressFamily(listenAddr))
+	}
+
+	backends, resolvedTargets, diags := ToLBBackendSetType(ctx, lb.Backends, modelBackends, targets)
 	respDiags.Append(diags...)
 
-	ports, diags := ToLBPortSetType(ctx, lb.Ports)
+	ports, diags := ToLBPortSetType(ctx, lb.Ports, modelBackends, targets)
 	respDiags.Append(diags...)
 
 	config, diags := common.ToConfigMapType(ctx, common.ToNullableConfig(lb.Config), m.Config)
@@ -412,6 +544,7 @@ func (r LxdNetworkLBResource) SyncState(ctx context.Context, tfState *tfsdk.Stat
 	m.Backends = backends
 	m.Ports = ports
 	m.Config = config
+	m.ResolvedTargets = resolvedTargets
 
 	if respDiags.HasError() {
 		return respDiags
@@ -421,26 +554,164 @@ func (r LxdNetworkLBResource) SyncState(ctx context.Context, tfState *tfsdk.Stat
 }
 
 type LxdNetworkLBBackendModel struct {
-	Name          types.String `tfsdk:"name"`
-	Description   types.String `tfsdk:"description"`
-	TargetAddress types.String `tfsdk:"target_address"`
-	TargetPort    types.String `tfsdk:"target_port"`
+	Name            types.String `tfsdk:"name"`
+	Description     types.String `tfsdk:"description"`
+	TargetAddress   types.String `tfsdk:"target_address"`
+	TargetPort      types.String `tfsdk:"target_port"`
+	TargetInstances types.Set    `tfsdk:"target_instances"`
+	TargetSelector  types.Map    `tfsdk:"target_selector"`
+	TargetNIC       types.String `tfsdk:"target_nic"`
 }
 
-// ToLBBackendList converts network LB backend from types.Set into
-// list of API backends.
-func ToLBBackendList(ctx context.Context, backendsSet types.Set) ([]api.NetworkLoadBalancerBackend, diag.Diagnostics) {
+// hasInstanceTargets returns true if the backend targets are resolved
+// from instances instead of using a static target address.
+func (m LxdNetworkLBBackendModel) hasInstanceTargets() bool {
+	return m.TargetAddress.IsNull()
+}
+
+// hasInstanceTargets returns true if any of the backends resolves its
+// targets from instances.
+func hasInstanceTargets(backends []LxdNetworkLBBackendModel) bool {
+	for _, b := range backends {
+		if b.hasInstanceTargets() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toLBBackendModels converts network LB backends from types.Set into
+// a list of backend models.
+func toLBBackendModels(ctx context.Context, backendsSet types.Set) ([]LxdNetworkLBBackendModel, diag.Diagnostics) {
 	if backendsSet.IsNull() || backendsSet.IsUnknown() {
-		return []api.NetworkLoadBalancerBackend{}, nil
+		return nil, nil
 	}
 
-	modelBackends := make([]LxdNetworkLBBackendModel, 0, len(backendsSet.Elements()))
-	diags := backendsSet.ElementsAs(ctx, &modelBackends, false)
+	backends := make([]LxdNetworkLBBackendModel, 0, len(backendsSet.Elements()))
+	diags := backendsSet.ElementsAs(ctx, &backends, false)
 	if diags.HasError() {
 		return nil, diags
 	}
 
-	// Convert into API network LB backends.
+	return backends, nil
+}
+
+// lbBackendTarget identifies an instance resolved by the backend
+// referencing instances.
+type lbBackendTarget struct {
+	Backend  string
+	Instance string
+}
+
+// name returns the name of the API backend created for the target.
+func (t lbBackendTarget) name() string {
+	return toLBBackendName(t.Backend, t.Instance)
+}
+
+// resolveLBBackendTargets resolves the addresses of instances referenced
+// by the LB backends. Each resolved instance becomes a separate LXD backend
+// named "<backend>-<instance>". An error is returned if the name of such
+// backend collides with the name of another backend.
+func resolveLBBackendTargets(server lxd.InstanceServer, backends []LxdNetworkLBBackendModel, family string) (map[lbBackendTarget]string, error) {
+	targets := make(map[lbBackendTarget]string)
+	for _, b := range backends {
+		if !b.hasInstanceTargets() {
+			continue
+		}
+
+		if b.TargetInstances.IsUnknown() || b.TargetSelector.IsUnknown() || b.TargetNIC.IsUnknown() {
+			return nil, fmt.Errorf("Targets of backend %q are not known yet", b.Name.ValueString())
+		}
+
+		var names []string
+		for _, v := range b.TargetInstances.Elements() {
+			name, ok := v.(types.String)
+			if ok {
+				names = append(names, name.ValueString())
+			}
+		}
+
+		selector := make(map[string]string, len(b.TargetSelector.Elements()))
+		for k, v := range b.TargetSelector.Elements() {
+			value, ok := v.(types.String)
+			if ok {
+				selector[k] = value.ValueString()
+			}
+		}
+
+		addresses, err := common.ResolveInstanceAddresses(server, names, selector, b.TargetNIC.ValueString(), family)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to resolve targets of backend %q: %w", b.Name.ValueString(), err)
+		}
+
+		for instName, address := range addresses {
+			targets[lbBackendTarget{Backend: b.Name.ValueString(), Instance: instName}] = address
+		}
+	}
+
+	err := checkLBBackendTargets(backends, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// checkLBBackendTargets returns an error if the name of the API backend
+// created for any of the resolved targets collides with the name of
+// another backend or target.
+func checkLBBackendTargets(backends []LxdNetworkLBBackendModel, targets map[lbBackendTarget]string) error {
+	names := make(map[string]string, len(backends)+len(targets))
+	for _, b := range backends {
+		names[b.Name.ValueString()] = fmt.Sprintf("backend %q", b.Name.ValueString())
+	}
+
+	// Check targets in a stable order for consistent errors.
+	sortedTargets := make([]lbBackendTarget, 0, len(targets))
+	for target := range targets {
+		sortedTargets = append(sortedTargets, target)
+	}
+
+	sort.Slice(sortedTargets, func(i, j int) bool {
+		if sortedTargets[i].Backend != sortedTargets[j].Backend {
+			return sortedTargets[i].Backend < sortedTargets[j].Backend
+		}
+
+		return sortedTargets[i].Instance < sortedTargets[j].Instance
+	})
+
+	for _, target := range sortedTargets {
+		other, ok := names[target.name()]
+		if ok {
+			return fmt.Errorf("Name %q of the backend created for instance %q of backend %q collides with %s", target.name(), target.Instance, target.Backend, other)
+		}
+
+		names[target.name()] = fmt.Sprintf("instance %q of backend %q", target.Instance, target.Backend)
+	}
+
+	return nil
+}
+
+// lbResolvedTargets returns the addresses of the resolved targets keyed by
+// the names of the API backends created for them.
+func lbResolvedTargets(targets map[lbBackendTarget]string) map[string]string {
+	if targets == nil {
+		return nil
+	}
+
+	resolved := make(map[string]string, len(targets))
+	for target, address := range targets {
+		resolved[target.name()] = address
+	}
+
+	return resolved
+}
+
+// ToLBBackendList converts network LB backend models into list of API
+// backends. Backends referencing instances are expanded into one API
+// backend per resolved target.
+func ToLBBackendList(modelBackends []LxdNetworkLBBackendModel, targets map[lbBackendTarget]string) []api.NetworkLoadBalancerBackend {
 	backends := make([]api.NetworkLoadBalancerBackend, 0, len(modelBackends))
 	for _, b := range modelBackends {
 		backend := api.NetworkLoadBalancerBackend{
@@ -450,39 +721,139 @@ func ToLBBackendList(ctx context.Context, backendsSet types.Set) ([]api.NetworkL
 			TargetPort:    b.TargetPort.ValueString(),
 		}
 
-		backends = append(backends, backend)
+		if !b.hasInstanceTargets() {
+			backends = append(backends, backend)
+			continue
+		}
+
+		for _, target := range lbBackendTargets(b.Name.ValueString(), targets) {
+			targetBackend := backend
+			targetBackend.Name = target.name()
+			targetBackend.TargetAddress = targets[target]
+			backends = append(backends, targetBackend)
+		}
 	}
 
-	return backends, nil
+	return backends
 }
 
 // ToLBBackendSetType converts list of API network LB backends into types.Set.
-func ToLBBackendSetType(ctx context.Context, backends []api.NetworkLoadBalancerBackend) (types.Set, diag.Diagnostics) {
+// API backends created for backends referencing instances are collapsed back
+// into the corresponding backend model and returned as resolved targets.
+// The given targets are used to find the backend of such API backends, see
+// findLBBackendGroup.
+func ToLBBackendSetType(ctx context.Context, backends []api.NetworkLoadBalancerBackend, modelBackends []LxdNetworkLBBackendModel, targets map[lbBackendTarget]string) (types.Set, types.Map, diag.Diagnostics) {
+	backendType := map[string]attr.Type{
+		"name":             types.StringType,
+		"description":      types.StringType,
+		"target_address":   types.StringType,
+		"target_port":      types.StringType,
+		"target_instances": types.SetType{ElemType: types.StringType},
+		"target_selector":  types.MapType{ElemType: types.StringType},
+		"target_nic":       types.StringType,
+	}
+
 	backendList := make([]LxdNetworkLBBackendModel, 0, len(backends))
+	resolved := make(map[string]string)
+
 	for _, b := range backends {
+		if findLBBackendGroup(b.Name, modelBackends, targets) != "" {
+			resolved[b.Name] = b.TargetAddress
+			continue
+		}
+
 		targetPort := types.StringNull()
 		if b.TargetPort != "" {
 			targetPort = types.StringValue(b.TargetPort)
 		}
 
 		backend := LxdNetworkLBBackendModel{
-			Name:          types.StringValue(b.Name),
-			Description:   types.StringValue(b.Description),
-			TargetAddress: types.StringValue(b.TargetAddress),
-			TargetPort:    targetPort,
+			Name:            types.StringValue(b.Name),
+			Description:     types.StringValue(b.Description),
+			TargetAddress:   types.StringValue(b.TargetAddress),
+			TargetPort:      targetPort,
+			TargetInstances: types.SetNull(types.StringType),
+			TargetSelector:  types.MapNull(types.StringType),
+			TargetNIC:       types.StringNull(),
 		}
 
 		backendList = append(backendList, backend)
 	}
 
-	backendType := map[string]attr.Type{
-		"name":           types.StringType,
-		"description":    types.StringType,
-		"target_address": types.StringType,
-		"target_port":    types.StringType,
+	// Backends referencing instances are kept as configured.
+	for _, b := range modelBackends {
+		if b.hasInstanceTargets() {
+			backendList = append(backendList, b)
+		}
 	}
 
-	return types.SetValueFrom(ctx, types.ObjectType{AttrTypes: backendType}, backendList)
+	var respDiags diag.Diagnostics
+
+	backendSet, diags := types.SetValueFrom(ctx, types.ObjectType{AttrTypes: backendType}, backendList)
+	respDiags.Append(diags...)
+
+	resolvedTargets := types.MapNull(types.StringType)
+	if hasInstanceTargets(modelBackends) {
+		resolvedTargets, diags = types.MapValueFrom(ctx, types.StringType, resolved)
+		respDiags.Append(diags...)
+	}
+
+	return backendSet, resolvedTargets, respDiags
+}
+
+// findLBBackendGroup returns the name of the backend referencing instances
+// for which the API backend with the given name was created. An empty
+// string is returned if the API backend does not belong to such backend.
+// API backends of the resolved targets are matched exactly, while other
+// API backends (e.g. of instances that no longer exist) are matched by
+// the longest backend name prefix.
+func findLBBackendGroup(name string, modelBackends []LxdNetworkLBBackendModel, targets map[lbBackendTarget]string) string {
+	for _, b := range modelBackends {
+		if b.Name.ValueString() == name {
+			// Backend is defined explicitly.
+			return ""
+		}
+	}
+
+	for target := range targets {
+		if target.name() == name && isLBBackendGroup(target.Backend, modelBackends) {
+			return target.Backend
+		}
+	}
+
+	group := ""
+	for _, b := range modelBackends {
+		backendName := b.Name.ValueString()
+		// Prefer the longest matching backend name.
+		if b.hasInstanceTargets() && strings.HasPrefix(name, backendName+"-") && len(backendName) > len(group) {
+			group = backendName
+		}
+	}
+
+	return group
+}
+
+// lbBackendTargets returns the targets resolved by the backend with the
+// given name, sorted by the instance name.
+func lbBackendTargets(group string, targets map[lbBackendTarget]string) []lbBackendTarget {
+	result := make([]lbBackendTarget, 0, len(targets))
+	for target := range targets {
+		if target.Backend == group {
+			result = append(result, target)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Instance < result[j].Instance
+	})
+
+	return result
+}
+
+// toLBBackendName returns the name of the API backend created for the
+// instance resolved by the backend with the given name.
+func toLBBackendName(group string, instanceName string) string {
+	return group + "-" + instanceName
 }
 
 type NetworkLBPortModel struct {
@@ -493,8 +864,9 @@ type NetworkLBPortModel struct {
 }
 
 // ToLBPortList converts network LB backend from types.Set into
-// list of API ports.
-func ToLBPortList(ctx context.Context, portSet types.Set) ([]api.NetworkLoadBalancerPort, diag.Diagnostics) {
+// list of API ports. Target backends referencing instances are replaced
+// with the API backends created for their resolved targets.
+func ToLBPortList(ctx context.Context, portSet types.Set, modelBackends []LxdNetworkLBBackendModel, targets map[lbBackendTarget]string) ([]api.NetworkLoadBalancerPort, diag.Diagnostics) {
 	if portSet.IsNull() || portSet.IsUnknown() {
 		return []api.NetworkLoadBalancerPort{}, nil
 	}
@@ -517,11 +889,23 @@ func ToLBPortList(ctx context.Context, portSet types.Set) ([]api.NetworkLoadBala
 			}
 		}
 
+		targetBackends := make([]string, 0, len(backends))
+		for _, name := range backends {
+			if !isLBBackendGroup(name, modelBackends) {
+				targetBackends = append(targetBackends, name)
+				continue
+			}
+
+			for _, target := range lbBackendTargets(name, targets) {
+				targetBackends = append(targetBackends, target.name())
+			}
+		}
+
 		port := api.NetworkLoadBalancerPort{
 			Description:   p.Description.ValueString(),
 			Protocol:      p.Protocol.ValueString(),
 			ListenPort:    p.ListenPort.ValueString(),
-			TargetBackend: backends,
+			TargetBackend: targetBackends,
 		}
 
 		ports = append(ports, port)
@@ -531,7 +915,9 @@ func ToLBPortList(ctx context.Context, portSet types.Set) ([]api.NetworkLoadBala
 }
 
 // ToLBPortSetType converts list of API network LB ports into types.Set.
-func ToLBPortSetType(ctx context.Context, ports []api.NetworkLoadBalancerPort) (types.Set, diag.Diagnostics) {
+// Target API backends created for backends referencing instances are
+// replaced with the name of the corresponding backend.
+func ToLBPortSetType(ctx context.Context, ports []api.NetworkLoadBalancerPort, modelBackends []LxdNetworkLBBackendModel, targets map[lbBackendTarget]string) (types.Set, diag.Diagnostics) {
 	portType := map[string]attr.Type{
 		"description":    types.StringType,
 		"protocol":       types.StringType,
@@ -541,7 +927,19 @@ func ToLBPortSetType(ctx context.Context, ports []api.NetworkLoadBalancerPort) (
 
 	portList := make([]NetworkLBPortModel, 0, len(ports))
 	for _, p := range ports {
-		backends, diags := types.SetValueFrom(ctx, types.StringType, p.TargetBackend)
+		targetBackends := make([]string, 0, len(p.TargetBackend))
+		for _, name := range p.TargetBackend {
+			group := findLBBackendGroup(name, modelBackends, targets)
+			if group != "" {
+				name = group
+			}
+
+			if !slices.Contains(targetBackends, name) {
+				targetBackends = append(targetBackends, name)
+			}
+		}
+
+		backends, diags := types.SetValueFrom(ctx, types.StringType, targetBackends)
 		if diags.HasError() {
 			return types.SetNull(types.ObjectType{AttrTypes: portType}), diags
 		}
@@ -563,3 +961,42 @@ func ToLBPortSetType(ctx context.Context, ports []api.NetworkLoadBalancerPort) (
 func toLBName(networkName string, listenAddr string) string {
 	return networkName + "/" + listenAddr
 }
+
+// isLBBackendGroup returns true if the backend with the given name
+// references instances.
+func isLBBackendGroup(name string, modelBackends []LxdNetworkLBBackendModel) bool {
+	for _, b := range modelBackends {
+		if b.Name.ValueString() == name {
+			return b.hasInstanceTargets()
+		}
+	}
+
+	return false
+}
+
+// planResolvedTargets sets the planned resolved targets of a resource with
+// targets referencing instances. The targets remain known only if they
+// match the prior state. Otherwise, they are marked as unknown, because
+// referenced instances may be created or changed during the same apply.
+// Nil targets indicate that they could not be resolved during plan.
+func planResolvedTargets(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, targets map[string]string) diag.Diagnostics {
+	attrPath := path.Root("resolved_targets")
+	if targets != nil && !req.State.Raw.IsNull() {
+		var stateTargets types.Map
+		diags := req.State.GetAttribute(ctx, attrPath, &stateTargets)
+		if diags.HasError() {
+			return diags
+		}
+
+		planTargets, diags := types.MapValueFrom(ctx, types.StringType, targets)
+		if diags.HasError() {
+			return diags
+		}
+
+		if planTargets.Equal(stateTargets) {
+			return resp.Plan.SetAttribute(ctx, attrPath, stateTargets)
+		}
+	}
+
+	return resp.Plan.SetAttribute(ctx, attrPath, types.MapUnknown(types.StringType))
+}
@@ -0,0 +1,94 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testLBBackend(name string, targetAddress string) LxdNetworkLBBackendModel {
+	address := types.StringNull()
+	if targetAddress != "" {
+		address = types.StringValue(targetAddress)
+	}
+
+	return LxdNetworkLBBackendModel{
+		Name:            types.StringValue(name),
+		TargetAddress:   address,
+		TargetInstances: types.SetNull(types.StringType),
+		TargetSelector:  types.MapNull(types.StringType),
+		TargetNIC:       types.StringNull(),
+	}
+}
+
+func TestLBBackendTargets_overlappingNames(t *testing.T) {
+	backends := []LxdNetworkLBBackendModel{
+		testLBBackend("web", ""),
+		testLBBackend("web-a", ""),
+		testLBBackend("web-x", "10.0.0.100"),
+	}
+
+	targets := map[lbBackendTarget]string{
+		{Backend: "web", Instance: "a-y"}: "10.0.0.1",
+		{Backend: "web", Instance: "b"}:   "10.0.0.2",
+		{Backend: "web-a", Instance: "z"}: "10.0.0.3",
+	}
+
+	assert.Equal(t, []lbBackendTarget{
+		{Backend: "web", Instance: "a-y"},
+		{Backend: "web", Instance: "b"},
+	}, lbBackendTargets("web", targets))
+
+	assert.Equal(t, []lbBackendTarget{
+		{Backend: "web-a", Instance: "z"},
+	}, lbBackendTargets("web-a", targets))
+
+	assert.Equal(t, []api.NetworkLoadBalancerBackend{
+		{Name: "web-a-y", TargetAddress: "10.0.0.1"},
+		{Name: "web-b", TargetAddress: "10.0.0.2"},
+		{Name: "web-a-z", TargetAddress: "10.0.0.3"},
+		{Name: "web-x", TargetAddress: "10.0.0.100"},
+	}, ToLBBackendList(backends, targets))
+
+	// Resolved targets are matched exactly, even if a longer backend
+	// name is a prefix of the API backend name.
+	assert.Equal(t, "web", findLBBackendGroup("web-a-y", backends, targets))
+	assert.Equal(t, "web-a", findLBBackendGroup("web-a-z", backends, targets))
+
+	// Static backends are never considered targets.
+	assert.Empty(t, findLBBackendGroup("web-x", backends, targets))
+
+	// Unknown targets are matched by the longest backend name prefix.
+	assert.Equal(t, "web-a", findLBBackendGroup("web-a-gone", backends, targets))
+	assert.Equal(t, "web", findLBBackendGroup("web-gone", backends, targets))
+	assert.Empty(t, findLBBackendGroup("db-gone", backends, targets))
+}
+
+func TestCheckLBBackendTargets(t *testing.T) {
+	backends := []LxdNetworkLBBackendModel{
+		testLBBackend("web", ""),
+		testLBBackend("web-a", ""),
+		testLBBackend("web-x", "10.0.0.100"),
+	}
+
+	err := checkLBBackendTargets(backends, map[lbBackendTarget]string{
+		{Backend: "web", Instance: "a-y"}: "10.0.0.1",
+		{Backend: "web-a", Instance: "z"}: "10.0.0.2",
+	})
+	assert.NoError(t, err)
+
+	// Instance "x" of backend "web" collides with static backend "web-x".
+	err = checkLBBackendTargets(backends, map[lbBackendTarget]string{
+		{Backend: "web", Instance: "x"}: "10.0.0.1",
+	})
+	assert.EqualError(t, err, `Name "web-x" of the backend created for instance "x" of backend "web" collides with backend "web-x"`)
+
+	// Instance "a-z" of backend "web" collides with instance "z" of backend "web-a".
+	err = checkLBBackendTargets(backends, map[lbBackendTarget]string{
+		{Backend: "web", Instance: "a-z"}: "10.0.0.1",
+		{Backend: "web-a", Instance: "z"}: "10.0.0.2",
+	})
+	assert.EqualError(t, err, `Name "web-a-z" of the backend created for instance "z" of backend "web-a" collides with instance "a-z" of backend "web"`)
+}
@@ -260,6 +260,35 @@ func TestAccNetworkLB_withBackendAndPort_noTargetPort(t *testing.T) {
 	})
 }
 
+func TestAccNetworkLB_withInstanceTargets(t *testing.T) {
+	uplinkSubnet := acctest.GenerateSubnet()
+	ovnSubnet := acctest.GenerateSubnet()
+	instanceName := acctest.GenerateName(2, "")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.PreCheckAPIExtensions(t, "network_load_balancer")
+		},
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccNetworkLB_withInstanceTargets(instanceName, uplinkSubnet, ovnSubnet),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_network_lb.test", "backend.#", "1"),
+					resource.TestCheckResourceAttr("lxd_network_lb.test", "backend.0.name", "web"),
+					resource.TestCheckNoResourceAttr("lxd_network_lb.test", "backend.0.target_address"),
+					resource.TestCheckResourceAttr("lxd_network_lb.test", "backend.0.target_selector.role", "web"),
+					resource.TestCheckResourceAttr("lxd_network_lb.test", "port.0.target_backend.#", "1"),
+					resource.TestCheckResourceAttr("lxd_network_lb.test", "port.0.target_backend.0", "web"),
+					resource.TestCheckResourceAttr("lxd_network_lb.test", "resolved_targets.%", "1"),
+					resource.TestCheckResourceAttr("lxd_network_lb.test", "resolved_targets.web-"+instanceName, ovnSubnet.HostIPv4(2)),
+				),
+			},
+		},
+	})
+}
+
 func testAccNetworkLB_basic(uplinkSubnet acctest.Subnet, ovnSubnet acctest.Subnet) string {
 	lbRes := fmt.Sprintf(`
 resource "lxd_network_lb" "test" {
@@ -381,6 +410,55 @@ resource "lxd_network_lb" "test" {
 	return fmt.Sprintf("%s\n%s", ovnNetworkResource(uplinkSubnet, ovnSubnet), lbRes)
 }
 
+func testAccNetworkLB_withInstanceTargets(instanceName string, uplinkSubnet acctest.Subnet, ovnSubnet acctest.Subnet) string {
+	lbRes := fmt.Sprintf(`
+resource "lxd_instance" "instance" {
+  name  = "%[1]s"
+  image = "%[2]s"
+
+  labels = {
+    "role" = "web"
+  }
+
+  wait_for {
+    type = "ipv4"
+  }
+
+  device {
+    name = "eth0"
+    type = "nic"
+    properties = {
+      "network"      = lxd_network.ovn.name
+      "ipv4.address" = "%[3]s"
+    }
+  }
+}
+
+resource "lxd_network_lb" "test" {
+  network        = lxd_network.ovn.name
+  listen_address = "%[4]s"
+
+  backend {
+    name        = "web"
+    target_port = "80"
+
+    target_selector = {
+      "role" = "web"
+    }
+  }
+
+  port {
+    listen_port    = "8080"
+    target_backend = ["web"]
+  }
+
+  depends_on = [lxd_instance.instance]
+}
+`, instanceName, acctest.TestImage, ovnSubnet.HostIPv4(2), uplinkSubnet.HostIPv4(200))
+
+	return fmt.Sprintf("%s\n%s", ovnNetworkResource(uplinkSubnet, ovnSubnet), lbRes)
+}
+
 func testAccNetworkLB_withBackendAndPort_noDescription(backend api.NetworkLoadBalancerBackend, port api.NetworkLoadBalancerPort, uplinkSubnet acctest.Subnet, ovnSubnet acctest.Subnet) string {
 	args := []any{
 		backend.Name,               // 1
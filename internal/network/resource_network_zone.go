@@ -101,6 +101,27 @@ func (r *NetworkZoneResource) Configure(_ context.Context, req resource.Configur
 	r.provider = provider
 }
 
+// ModifyPlan checks whether the server supports network zones.
+func (r NetworkZoneResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip the checks on destroy or if the server is not reachable.
+	server := r.provider.PlanInstanceServer(ctx, req.Plan)
+	if server == nil {
+		return
+	}
+
+	var plan NetworkZoneModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(common.CheckResourceExtensions(server, "lxd_network_zone", "network_dns")...)
+
+	if plan.Project.ValueString() != provider_config.DefaultProject {
+		resp.Diagnostics.Append(common.CheckAttributeExtensions(server, path.Root("project"), "projects_networks_zones")...)
+	}
+}
+
 func (r NetworkZoneResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan NetworkZoneModel
 
@@ -144,6 +144,27 @@ func (r *NetworkZoneRecordResource) Configure(_ context.Context, req resource.Co
 	r.provider = provider
 }
 
+// ModifyPlan checks whether the server supports network zone records.
+func (r NetworkZoneRecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip the checks on destroy or if the server is not reachable.
+	server := r.provider.PlanInstanceServer(ctx, req.Plan)
+	if server == nil {
+		return
+	}
+
+	var plan NetworkZoneRecordModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(common.CheckResourceExtensions(server, "lxd_network_zone_record", "network_dns_records")...)
+
+	if plan.Project.ValueString() != provider_config.DefaultProject {
+		resp.Diagnostics.Append(common.CheckAttributeExtensions(server, path.Root("project"), "projects_networks_zones")...)
+	}
+}
+
 func (r NetworkZoneRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan NetworkZoneRecordModel
 
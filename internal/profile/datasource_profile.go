@@ -12,6 +12,19 @@ import (
 	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
 )
 
+// ProfileDataSourceModel resource data model that matches the schema.
+type ProfileDataSourceModel struct {
+	Name    types.String `tfsdk:"name"`
+	Project types.String `tfsdk:"project"`
+	Remote  types.String `tfsdk:"remote"`
+
+	// Computed.
+	Description types.String `tfsdk:"description"`
+	Devices     types.Set    `tfsdk:"device"`
+	Config      types.Map    `tfsdk:"config"`
+	Labels      types.Map    `tfsdk:"labels"`
+}
+
 func NewProfileDataSource() datasource.DataSource {
 	return &ProfileDataSource{}
 }
@@ -48,6 +61,11 @@ func (d *ProfileDataSource) Schema(_ context.Context, req datasource.SchemaReque
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+
+			"labels": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"device": schema.SetNestedBlock{
@@ -88,7 +106,7 @@ func (d *ProfileDataSource) Configure(_ context.Context, req datasource.Configur
 }
 
 func (d *ProfileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var state ProfileModel
+	var state ProfileDataSourceModel
 
 	diags := req.Config.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -114,6 +132,9 @@ func (d *ProfileDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	config, diags := common.ToConfigMapType(ctx, common.ToNullableConfig(profile.Config), state.Config)
 	resp.Diagnostics.Append(diags...)
 
+	labels, diags := types.MapValueFrom(ctx, types.StringType, common.LabelsFromConfig(profile.Config))
+	resp.Diagnostics.Append(diags...)
+
 	devices, diags := common.ToDeviceSetType(ctx, profile.Devices)
 	resp.Diagnostics.Append(diags...)
 
@@ -122,6 +143,7 @@ func (d *ProfileDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	state.Project = types.StringValue(project)
 	state.Devices = devices
 	state.Config = config
+	state.Labels = labels
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -3,6 +3,7 @@ package profile
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared"
@@ -28,12 +29,14 @@ import (
 
 // ProfileModel represents a LXD profile.
 type ProfileModel struct {
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	Project     types.String `tfsdk:"project"`
-	Remote      types.String `tfsdk:"remote"`
-	Devices     types.Set    `tfsdk:"device"`
-	Config      types.Map    `tfsdk:"config"`
+	Name             types.String `tfsdk:"name"`
+	Description      types.String `tfsdk:"description"`
+	Project          types.String `tfsdk:"project"`
+	Remote           types.String `tfsdk:"remote"`
+	Devices          types.Set    `tfsdk:"device"`
+	UnmanagedDevices types.String `tfsdk:"unmanaged_devices"`
+	Config           types.Map    `tfsdk:"config"`
+	Labels           types.Map    `tfsdk:"labels"`
 }
 
 // ProfileResource represent LXD profile resource.
@@ -87,8 +90,35 @@ func (r ProfileResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 			"config": schema.MapAttribute{
 				Optional:    true,
 				Computed:    true,
+				ElementType: common.ConfigValueType{},
+				Default:     mapdefault.StaticValue(types.MapValueMust(common.ConfigValueType{}, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(common.NoLabelKeyValidator{}),
+				},
+			},
+
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels of the profile, stored as user.label.* config keys",
 				ElementType: types.StringType,
-				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					// Prevent empty values.
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"unmanaged_devices": schema.StringAttribute{
+				Description: "Policy for devices not managed by Terraform: ignore, warn, remove (default), or adopt.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						common.UnmanagedDevicesIgnore,
+						common.UnmanagedDevicesWarn,
+						common.UnmanagedDevicesRemove,
+						common.UnmanagedDevicesAdopt,
+					),
+				},
 			},
 		},
 
@@ -145,6 +175,118 @@ func (r *ProfileResource) Configure(_ context.Context, req resource.ConfigureReq
 	r.provider = provider
 }
 
+// ModifyPlan validates the profile config keys and device properties
+// against the server metadata, and reports unmanaged devices.
+func (r *ProfileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.provider == nil {
+		// Nothing to do on destroy.
+		return
+	}
+
+	resp.Diagnostics.Append(r.validatePlanConfig(ctx, req)...)
+	resp.Diagnostics.Append(r.warnUnmanagedDevices(ctx, req)...)
+}
+
+// validatePlanConfig validates the planned profile config keys and device
+// properties against the server metadata. Validation is skipped for config
+// and devices that are unchanged.
+func (r *ProfileResource) validatePlanConfig(ctx context.Context, req resource.ModifyPlanRequest) diag.Diagnostics {
+	var plan, state ProfileModel
+
+	mode := r.provider.ConfigValidation()
+	if mode == common.ConfigValidationNone {
+		return nil
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	if diags.HasError() {
+		return diags
+	}
+
+	// Validate config keys and devices only when they change.
+	configChanged := true
+	devicesChanged := true
+
+	if !req.State.Raw.IsNull() {
+		diags := req.State.Get(ctx, &state)
+		if diags.HasError() {
+			return diags
+		}
+
+		configChanged = !plan.Config.Equal(state.Config)
+		devicesChanged = !plan.Devices.Equal(state.Devices)
+	}
+
+	if (!configChanged && !devicesChanged) || plan.Remote.IsUnknown() {
+		return nil
+	}
+
+	// Config keys and device properties do not depend on the project.
+	server, err := r.provider.InstanceServer(plan.Remote.ValueString(), "", "")
+	if err != nil {
+		return nil
+	}
+
+	if configChanged {
+		diags.Append(common.ValidateServerConfig(server, plan.Config, "instance", "", mode)...)
+	}
+
+	// Profiles can be applied to both containers and virtual machines.
+	if devicesChanged {
+		diags.Append(common.ValidateDevices(ctx, server, plan.Devices, path.Root("device"), "", mode)...)
+	}
+
+	return diags
+}
+
+// warnUnmanagedDevices reports devices of an existing profile that are
+// neither managed by Terraform nor declared in the configuration, if the
+// unmanaged devices policy is "warn".
+func (r *ProfileResource) warnUnmanagedDevices(ctx context.Context, req resource.ModifyPlanRequest) diag.Diagnostics {
+	var plan, state ProfileModel
+
+	if req.State.Raw.IsNull() {
+		return nil
+	}
+
+	diags := req.Plan.Get(ctx, &plan)
+	if diags.HasError() || plan.UnmanagedDevices.ValueString() != common.UnmanagedDevicesWarn {
+		return diags
+	}
+
+	diags = req.State.Get(ctx, &state)
+	if diags.HasError() {
+		return diags
+	}
+
+	declared, diags := common.ToDeviceMap(ctx, plan.Devices)
+	if diags.HasError() {
+		return diags
+	}
+
+	// Devices of a profile are not marked as managed by terraform, so
+	// the devices that are already in the state are considered managed.
+	stateDevices, diags := common.ToDeviceMap(ctx, state.Devices)
+	if diags.HasError() {
+		return diags
+	}
+
+	maps.Copy(declared, stateDevices)
+
+	server, err := r.provider.InstanceServer(state.Remote.ValueString(), state.Project.ValueString(), "")
+	if err != nil {
+		return nil
+	}
+
+	profileName := state.Name.ValueString()
+	profile, _, err := server.GetProfile(profileName)
+	if err != nil {
+		return nil
+	}
+
+	return common.UnmanagedDevicesWarnings(fmt.Sprintf("profile %q", profileName), profile.Devices, declared)
+}
+
 func (r ProfileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan ProfileModel
 
@@ -166,6 +308,9 @@ func (r ProfileResource) Create(ctx context.Context, req resource.CreateRequest,
 	config, diag := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diag...)
 
+	labels, diags := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+
 	devices, diags := common.ToDeviceMap(ctx, plan.Devices)
 	resp.Diagnostics.Append(diags...)
 
@@ -173,6 +318,16 @@ func (r ProfileResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	maps.Copy(config, common.LabelsToConfig(labels))
+
+	// Mark devices as managed by terraform when unmanaged devices
+	// are adopted.
+	if plan.UnmanagedDevices.ValueString() == common.UnmanagedDevicesAdopt {
+		for _, device := range devices {
+			device[common.UserManagedBy] = common.DeviceManagedByTerraform
+		}
+	}
+
 	profileName := plan.Name.ValueString()
 
 	profile := api.ProfilesPost{
@@ -257,10 +412,10 @@ func (r ProfileResource) Read(ctx context.Context, req resource.ReadRequest, res
 }
 
 func (r ProfileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan ProfileModel
+	var plan, state ProfileModel
 
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -274,7 +429,7 @@ func (r ProfileResource) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 
 	profileName := plan.Name.ValueString()
-	_, etag, err := server.GetProfile(profileName)
+	oldProfile, etag, err := server.GetProfile(profileName)
 	if err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve existing profile %q", profileName), err.Error())
 		return
@@ -283,6 +438,9 @@ func (r ProfileResource) Update(ctx context.Context, req resource.UpdateRequest,
 	config, diags := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diags...)
 
+	labels, diags := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
+
 	devices, diags := common.ToDeviceMap(ctx, plan.Devices)
 	resp.Diagnostics.Append(diags...)
 
@@ -290,6 +448,37 @@ func (r ProfileResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	maps.Copy(config, common.LabelsToConfig(labels))
+
+	// Preserve unmanaged devices if they are not tracked in the state.
+	// Devices of a profile are not marked as managed by terraform, so
+	// the devices that are not in the prior state are unmanaged.
+	policy := plan.UnmanagedDevices.ValueString()
+	if !common.TrackUnmanagedDevices(policy) {
+		stateDevices, diags := common.ToDeviceMap(ctx, state.Devices)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for deviceName, device := range oldProfile.Devices {
+			_, alreadyAdded := devices[deviceName]
+			_, inState := stateDevices[deviceName]
+			if !alreadyAdded && !inState {
+				devices[deviceName] = device
+			}
+		}
+	}
+
+	// Mark all devices of the profile as managed by terraform when
+	// unmanaged devices are adopted. The mark is removed from the
+	// state on read.
+	if policy == common.UnmanagedDevicesAdopt {
+		for _, device := range devices {
+			device[common.UserManagedBy] = common.DeviceManagedByTerraform
+		}
+	}
+
 	// Update profile.
 	profile := api.ProfilePut{
 		Description: plan.Description.ValueString(),
@@ -433,16 +622,50 @@ func (r ProfileResource) SyncState(ctx context.Context, tfState *tfsdk.State, se
 	}
 
 	// Convert config state and devices into schema types.
-	config, diags := common.ToConfigMapType(ctx, common.ToNullableConfig(profile.Config), m.Config)
+	// Labels are excluded from the config.
+	config, diags := common.ToConfigValueMapType(ctx, common.ToNullableConfig(common.StripLabels(profile.Config)), m.Config)
+	respDiags.Append(diags...)
+
+	labels, diags := common.ToLabelsMapType(ctx, profile.Config, m.Labels)
 	respDiags.Append(diags...)
 
-	devices, diags := common.ToDeviceSetType(ctx, profile.Devices)
+	// Devices of a profile are not marked as managed by terraform.
+	// Therefore, if unmanaged devices are not tracked, only devices that
+	// are already present in the state (or plan) are kept. On import, all
+	// devices are brought into the state.
+	syncDevices := profile.Devices
+	if !common.TrackUnmanagedDevices(m.UnmanagedDevices.ValueString()) && !m.Devices.IsNull() {
+		configuredDevices, diags := common.ToDeviceMap(ctx, m.Devices)
+		respDiags.Append(diags...)
+
+		syncDevices = make(map[string]map[string]string)
+		for deviceName := range configuredDevices {
+			device, ok := profile.Devices[deviceName]
+			if ok {
+				syncDevices[deviceName] = device
+			}
+		}
+	}
+
+	// Remove "user.managed-by" key written to adopted devices from the
+	// state to avoid config mismatch.
+	for deviceName, device := range syncDevices {
+		_, ok := device[common.UserManagedBy]
+		if ok {
+			device = maps.Clone(device)
+			delete(device, common.UserManagedBy)
+			syncDevices[deviceName] = device
+		}
+	}
+
+	devices, diags := common.ToDeviceSetType(ctx, syncDevices)
 	respDiags.Append(diags...)
 
 	m.Name = types.StringValue(profile.Name)
 	m.Description = types.StringValue(profile.Description)
 	m.Devices = devices
 	m.Config = config
+	m.Labels = labels
 
 	if respDiags.HasError() {
 		return respDiags
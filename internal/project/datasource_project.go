@@ -43,6 +43,11 @@ func (d *ProjectDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+
+			"labels": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
 		},
 	}
 }
@@ -88,9 +93,13 @@ func (d *ProjectDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	config, diags := common.ToConfigMapType(ctx, common.ToNullableConfig(project.Config), state.Config)
 	resp.Diagnostics.Append(diags...)
 
+	labels, diags := types.MapValueFrom(ctx, types.StringType, common.LabelsFromConfig(project.Config))
+	resp.Diagnostics.Append(diags...)
+
 	state.Name = types.StringValue(project.Name)
 	state.Description = types.StringValue(project.Description)
 	state.Config = config
+	state.Labels = labels
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -3,9 +3,12 @@ package project
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -15,6 +18,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
@@ -28,6 +32,7 @@ type ProjectModel struct {
 	Description types.String `tfsdk:"description"`
 	Remote      types.String `tfsdk:"remote"`
 	Config      types.Map    `tfsdk:"config"`
+	Labels      types.Map    `tfsdk:"labels"`
 }
 
 // ProjectResource represent LXD project resource.
@@ -65,8 +70,22 @@ func (r ProjectResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 			"config": schema.MapAttribute{
 				Optional:    true,
 				Computed:    true,
+				ElementType: common.ConfigValueType{},
+				Default:     mapdefault.StaticValue(types.MapValueMust(common.ConfigValueType{}, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(common.NoLabelKeyValidator{}),
+				},
+			},
+
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels of the project, stored as user.label.* config keys",
 				ElementType: types.StringType,
-				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					// Prevent empty values.
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
 			},
 
 			"remote": schema.StringAttribute{
@@ -91,6 +110,46 @@ func (r *ProjectResource) Configure(_ context.Context, req resource.ConfigureReq
 	r.provider = provider
 }
 
+// ModifyPlan validates the project config keys against the server metadata.
+func (r *ProjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.provider == nil {
+		// Nothing to do on destroy.
+		return
+	}
+
+	mode := r.provider.ConfigValidation()
+	if mode == common.ConfigValidationNone {
+		return
+	}
+
+	var plan, state ProjectModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Validate config keys only when the config changes.
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() || plan.Config.Equal(state.Config) {
+			return
+		}
+	}
+
+	if plan.Remote.IsUnknown() {
+		return
+	}
+
+	// Config keys do not depend on the project.
+	server, err := r.provider.InstanceServer(plan.Remote.ValueString(), "", "")
+	if err != nil {
+		return
+	}
+
+	resp.Diagnostics.Append(common.ValidateServerConfig(server, plan.Config, "project", "", mode)...)
+}
+
 func (r ProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan ProjectModel
 
@@ -103,10 +162,15 @@ func (r ProjectResource) Create(ctx context.Context, req resource.CreateRequest,
 	// Convert project config schema to map.
 	config, diag := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diag...)
+
+	labels, diag := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diag...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	maps.Copy(config, common.LabelsToConfig(labels))
+
 	remote := plan.Remote.ValueString()
 	projectName := plan.Name.ValueString()
 	server, err := r.provider.InstanceServer(remote, projectName, "")
@@ -187,12 +251,16 @@ func (r ProjectResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	userConfig, diags := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diags...)
+
+	labels, diags := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Merge project state and user defined configuration.
 	config := common.MergeConfig(project.Config, userConfig, plan.ComputedKeys())
+	maps.Copy(config, common.LabelsToConfig(labels))
 
 	// Update project.
 	newProject := api.ProjectPut{
@@ -294,15 +362,20 @@ func (r ProjectResource) SyncState(ctx context.Context, tfState *tfsdk.State, se
 	}
 
 	// Extract user defined config and merge it with current config state.
-	stateConfig := common.StripConfig(project.Config, m.Config, m.ComputedKeys())
+	// Labels are excluded from the config.
+	stateConfig := common.StripConfig(common.StripLabels(project.Config), m.Config, m.ComputedKeys())
 
 	// Convert config state into schema type.
-	config, diags := common.ToConfigMapType(ctx, stateConfig, m.Config)
+	config, diags := common.ToConfigValueMapType(ctx, stateConfig, m.Config)
+	respDiags.Append(diags...)
+
+	labels, diags := common.ToLabelsMapType(ctx, project.Config, m.Labels)
 	respDiags.Append(diags...)
 
 	m.Name = types.StringValue(project.Name)
 	m.Description = types.StringValue(project.Description)
 	m.Config = config
+	m.Labels = labels
 
 	if respDiags.HasError() {
 		return respDiags
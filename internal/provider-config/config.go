@@ -15,11 +15,41 @@ import (
 	lxdConfig "github.com/canonical/lxd/lxc/config"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
 )
 
+// minLXDVersion is the minimum LXD version supported by the provider.
+const minLXDVersion = "5.0.0"
+
 // supportedLXDVersions defines LXD versions that are supported by the provider.
-const supportedLXDVersions = ">= 5.0.0"
+const supportedLXDVersions = ">= " + minLXDVersion
+
+// extensionLXDVersions lists API extensions required by individual
+// resources and attributes, together with an LXD version that is known
+// to support them. Extensions introduced before the minimum supported
+// LXD version are listed with that version.
+var extensionLXDVersions = map[string]string{
+	"access_management":       "5.21.0",
+	"auth_bearer":             "6.5.0",
+	"explicit_trust_token":    "5.21.0",
+	"instances_rebuild":       "5.21.0",
+	"network_dns":             minLXDVersion,
+	"network_dns_records":     minLXDVersion,
+	"network_load_balancer":   "5.21.0",
+	"projects_networks_zones": "5.21.0",
+	"storage_buckets":         "5.5.0",
+	"storage_buckets_local":   "5.6.0",
+}
+
+// SupportedLXDVersion returns the minimum LXD version that supports the
+// given API extension, or false if the extension is not known.
+func SupportedLXDVersion(extension string) (string, bool) {
+	version, ok := extensionLXDVersions[extension]
+	return version, ok
+}
 
 // DefaultProject is the default LXD project used by the provider when no project is specified.
 const DefaultProject = "default"
@@ -59,6 +89,10 @@ type LxdProviderConfig struct {
 	// resource or data source does not explicitly specify a remote.
 	defaultRemote string
 
+	// configValidation determines how config keys that are not described
+	// by the server metadata are reported ("error", "warning", or "none").
+	configValidation string
+
 	// mux is a lock that handle concurrent reads/writes to the LXD config.
 	mux sync.RWMutex
 }
@@ -245,7 +279,8 @@ func (p *LxdProviderConfig) server(remoteName string) (lxd.Server, error) {
 				Type: "client",
 			}
 
-			if instServer.HasExtension("explicit_trust_token") {
+			hasTrustToken := instServer.HasExtension("explicit_trust_token")
+			if hasTrustToken {
 				req.TrustToken = remote.TrustToken
 			} else {
 				req.Password = remote.TrustToken // nolint: staticcheck
@@ -261,6 +296,12 @@ func (p *LxdProviderConfig) server(remoteName string) (lxd.Server, error) {
 			}
 
 			if apiServer.Auth != "trusted" {
+				// Without the "explicit_trust_token" API extension, the token is
+				// sent as a trust password, which the server may not accept.
+				if !hasTrustToken {
+					return nil, fmt.Errorf("Unable to authenticate with remote server: %v (server does not support the LXD API extension \"explicit_trust_token\", please upgrade the LXD server to version %s or later)", errCert, extensionLXDVersions["explicit_trust_token"])
+				}
+
 				return nil, fmt.Errorf("Unable to authenticate with remote server: %v", errCert)
 			}
 		}
@@ -374,7 +415,13 @@ func (p *LxdProviderConfig) ToHCL() string {
 
 	var b strings.Builder
 	b.WriteString(`provider "lxd" {` + "\n")
-	fmt.Fprintf(&b, "  default_remote = %q\n\n", p.defaultRemote)
+	fmt.Fprintf(&b, "  default_remote = %q\n", p.defaultRemote)
+
+	if p.configValidation != "" {
+		fmt.Fprintf(&b, "  config_validation = %q\n", p.configValidation)
+	}
+
+	b.WriteString("\n")
 
 	builtinRemoteNames := []string{""}
 	for name := range builtinRemotes() {
@@ -417,6 +464,45 @@ func (p *LxdProviderConfig) ToHCL() string {
 	return b.String()
 }
 
+// SetConfigValidation sets the mode of the config key validation.
+func (p *LxdProviderConfig) SetConfigValidation(mode string) {
+	p.configValidation = mode
+}
+
+// ConfigValidation returns the mode of the config key validation. Invalid
+// config keys are reported as warnings by default.
+func (p *LxdProviderConfig) ConfigValidation() string {
+	if p.configValidation == "" {
+		return "warning"
+	}
+
+	return p.configValidation
+}
+
+// PlanInstanceServer returns a LXD InstanceServer client for the remote of
+// the given plan, which is used to check the API extensions supported by
+// the server during plan. It returns nil if the resource is being
+// destroyed, if the remote is not known yet, or if the server is not
+// reachable, in which case the checks are skipped.
+func (p *LxdProviderConfig) PlanInstanceServer(ctx context.Context, plan tfsdk.Plan) lxd.InstanceServer {
+	if p == nil || plan.Raw.IsNull() {
+		return nil
+	}
+
+	var remote types.String
+	diags := plan.GetAttribute(ctx, path.Root("remote"), &remote)
+	if diags.HasError() || remote.IsUnknown() {
+		return nil
+	}
+
+	server, err := p.InstanceServer(remote.ValueString(), "", "")
+	if err != nil {
+		return nil
+	}
+
+	return server
+}
+
 // DefaultTimeout returns the default time period after which a resource
 // action (read/create/update/delete) is expected to time out.
 func (p *LxdProviderConfig) DefaultTimeout() time.Duration {
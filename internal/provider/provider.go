@@ -44,8 +44,9 @@ type LxdProviderRemoteModel struct {
 
 // LxdProviderModel represents provider's schema.
 type LxdProviderModel struct {
-	Remotes       []LxdProviderRemoteModel `tfsdk:"remote"`
-	DefaultRemote types.String             `tfsdk:"default_remote"`
+	Remotes          []LxdProviderRemoteModel `tfsdk:"remote"`
+	DefaultRemote    types.String             `tfsdk:"default_remote"`
+	ConfigValidation types.String             `tfsdk:"config_validation"`
 }
 
 // LxdProvider ...
@@ -74,6 +75,14 @@ func (p *LxdProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *
 				Optional:    true,
 				Description: "Name of the default LXD remote to use when no remote is specified in the resource. If two or more remotes are defined, one must be set as the default.",
 			},
+
+			"config_validation": schema.StringAttribute{
+				Optional:    true,
+				Description: "How config keys that are not supported by the server are reported during plan. Defaults to \"warning\".",
+				Validators: []validator.String{
+					stringvalidator.OneOf("error", "warning", "none"),
+				},
+			},
 		},
 
 		Blocks: map[string]schema.Block{
@@ -303,6 +312,8 @@ func (p *LxdProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		return
 	}
 
+	lxdProvider.SetConfigValidation(data.ConfigValidation.ValueString())
+
 	// Avoid logging sensitive provider internals (tokens/keys). Log only
 	// minimal, non-sensitive metadata instead.
 	tflog.Debug(ctx, "LXD Provider configured", map[string]any{
@@ -322,8 +333,11 @@ func (p *LxdProvider) Resources(_ context.Context) []func() resource.Resource {
 		image.NewImageResource,
 		instance.NewInstanceResource,
 		instance.NewInstanceFileResource,
+		instance.NewInstanceBackupResource,
+		instance.NewInstanceExecResource,
 		instance.NewInstanceSnapshotResource,
 		instance.NewInstanceDeviceResource,
+		instance.NewInstanceGroupResource,
 		network.NewNetworkResource,
 		network.NewNetworkAclResource,
 		network.NewNetworkForwardResource,
@@ -356,6 +370,11 @@ func (p *LxdProvider) DataSources(_ context.Context) []func() datasource.DataSou
 		auth.NewAuthIdentityDataSource,
 		image.NewImageDataSource,
 		instance.NewInstanceDataSource,
+		instance.NewInstanceFileDataSource,
+		instance.NewInstanceLogsDataSource,
+		instance.NewInstanceSnapshotsDataSource,
+		instance.NewInstanceStateDataSource,
+		instance.NewInstancesDataSource,
 		network.NewNetworkDataSource,
 		profile.NewProfileDataSource,
 		project.NewProjectDataSource,
@@ -104,8 +104,8 @@ func (r StorageBucketResource) Schema(ctx context.Context, req resource.SchemaRe
 			"config": schema.MapAttribute{
 				Optional:    true,
 				Computed:    true,
-				ElementType: types.StringType,
-				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				ElementType: common.ConfigValueType{},
+				Default:     mapdefault.StaticValue(types.MapValueMust(common.ConfigValueType{}, map[string]attr.Value{})),
 			},
 
 			// Computed.
@@ -132,6 +132,58 @@ func (r *StorageBucketResource) Configure(_ context.Context, req resource.Config
 	r.provider = provider
 }
 
+// ModifyPlan checks whether the server supports storage buckets on the
+// given pool, and validates the bucket config keys against the server
+// metadata of the storage pool driver.
+func (r *StorageBucketResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip the checks on destroy or if the server is not reachable.
+	server := r.provider.PlanInstanceServer(ctx, req.Plan)
+	if server == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(common.CheckResourceExtensions(server, "lxd_storage_bucket", "storage_buckets")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state StorageBucketModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Pool.IsUnknown() {
+		return
+	}
+
+	// Skip the checks if the pool does not exist yet.
+	pool, _, err := server.GetStoragePool(plan.Pool.ValueString())
+	if err != nil {
+		return
+	}
+
+	// Buckets on pools other than cephobject are backed by local storage.
+	if pool.Driver != "cephobject" {
+		resp.Diagnostics.Append(common.CheckAttributeExtensions(server, path.Root("pool"), "storage_buckets_local")...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	mode := r.provider.ConfigValidation()
+	if mode == common.ConfigValidationNone {
+		return
+	}
+
+	// Validate config keys only when the config changes.
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() || plan.Config.Equal(state.Config) {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(common.ValidateServerConfig(server, plan.Config, "storage-"+pool.Driver, "bucket-conf", mode)...)
+}
+
 func (r StorageBucketResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan StorageBucketModel
 
@@ -347,7 +399,7 @@ func (r StorageBucketResource) SyncState(ctx context.Context, tfState *tfsdk.Sta
 	stateConfig := common.StripConfig(bucket.Config, m.Config, m.ComputedKeys())
 
 	// Convert config state into schema type.
-	config, diags := common.ToConfigMapType(ctx, stateConfig, m.Config)
+	config, diags := common.ToConfigValueMapType(ctx, stateConfig, m.Config)
 	respDiags.Append(diags...)
 
 	m.Name = types.StringValue(bucket.Name)
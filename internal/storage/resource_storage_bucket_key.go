@@ -139,6 +139,17 @@ func (r *StorageBucketKeyResource) Configure(_ context.Context, req resource.Con
 	r.provider = provider
 }
 
+// ModifyPlan checks whether the server supports storage bucket keys.
+func (r StorageBucketKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip the checks on destroy or if the server is not reachable.
+	server := r.provider.PlanInstanceServer(ctx, req.Plan)
+	if server == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(common.CheckResourceExtensions(server, "lxd_storage_bucket_key", "storage_buckets")...)
+}
+
 func (r StorageBucketKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan StorageBucketKeyModel
 
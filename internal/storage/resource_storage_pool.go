@@ -102,8 +102,8 @@ func (r StoragePoolResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"config": schema.MapAttribute{
 				Optional:    true,
 				Computed:    true,
-				ElementType: types.StringType,
-				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				ElementType: common.ConfigValueType{},
+				Default:     mapdefault.StaticValue(types.MapValueMust(common.ConfigValueType{}, map[string]attr.Value{})),
 			},
 
 			// Contains only local (member-specific) storage pool configuration that
@@ -509,7 +509,7 @@ func (r StoragePoolResource) SyncState(ctx context.Context, tfState *tfsdk.State
 	// Merge current storage pool configuration with user provided configuration, stripping away
 	// computed fields that were not set by the user.
 	poolConfig := common.StripConfig(pool.Config, m.Config, m.ComputedKeys(pool.Driver))
-	configValue, diags := common.ToConfigMapType(ctx, poolConfig, m.Config)
+	configValue, diags := common.ToConfigValueMapType(ctx, poolConfig, m.Config)
 	if diags.HasError() {
 		return diags
 	}
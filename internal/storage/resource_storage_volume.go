@@ -3,10 +3,12 @@ package storage
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -35,6 +37,7 @@ type StorageVolumeModel struct {
 	Target      types.String `tfsdk:"target"`
 	Remote      types.String `tfsdk:"remote"`
 	Config      types.Map    `tfsdk:"config"`
+	Labels      types.Map    `tfsdk:"labels"`
 
 	// Computed.
 	Location types.String `tfsdk:"location"`
@@ -128,8 +131,22 @@ func (r StorageVolumeResource) Schema(_ context.Context, _ resource.SchemaReques
 			"config": schema.MapAttribute{
 				Optional:    true,
 				Computed:    true,
+				ElementType: common.ConfigValueType{},
+				Default:     mapdefault.StaticValue(types.MapValueMust(common.ConfigValueType{}, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(common.NoLabelKeyValidator{}),
+				},
+			},
+
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				Description: "Labels of the storage volume, stored as user.label.* config keys",
 				ElementType: types.StringType,
-				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					// Prevent empty values.
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
 			},
 
 			// Computed.
@@ -156,6 +173,52 @@ func (r *StorageVolumeResource) Configure(_ context.Context, req resource.Config
 	r.provider = provider
 }
 
+// ModifyPlan validates the volume config keys against the server metadata
+// of the storage pool driver.
+func (r *StorageVolumeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.provider == nil {
+		// Nothing to do on destroy.
+		return
+	}
+
+	mode := r.provider.ConfigValidation()
+	if mode == common.ConfigValidationNone {
+		return
+	}
+
+	var plan, state StorageVolumeModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Validate config keys only when the config changes.
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() || plan.Config.Equal(state.Config) {
+			return
+		}
+	}
+
+	if plan.Remote.IsUnknown() || plan.Pool.IsUnknown() {
+		return
+	}
+
+	server, err := r.provider.InstanceServer(plan.Remote.ValueString(), "", "")
+	if err != nil {
+		return
+	}
+
+	// Skip validation if the pool does not exist yet.
+	pool, _, err := server.GetStoragePool(plan.Pool.ValueString())
+	if err != nil {
+		return
+	}
+
+	resp.Diagnostics.Append(common.ValidateServerConfig(server, plan.Config, "storage-"+pool.Driver, "volume-conf", mode)...)
+}
+
 func (r StorageVolumeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan StorageVolumeModel
 
@@ -177,10 +240,15 @@ func (r StorageVolumeResource) Create(ctx context.Context, req resource.CreateRe
 	// Convert volume config to map.
 	config, diags := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diags...)
+
+	labels, diags := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	maps.Copy(config, common.LabelsToConfig(labels))
+
 	poolName := plan.Pool.ValueString()
 	volName := plan.Name.ValueString()
 
@@ -267,12 +335,16 @@ func (r StorageVolumeResource) Update(ctx context.Context, req resource.UpdateRe
 
 	userConfig, diags := common.ToConfigMap(ctx, plan.Config)
 	resp.Diagnostics.Append(diags...)
+
+	labels, diags := common.ToConfigMap(ctx, plan.Labels)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// Merge volume config and user defined config.
 	config := common.MergeConfig(vol.Config, userConfig, plan.ComputedKeys())
+	maps.Copy(config, common.LabelsToConfig(labels))
 
 	volReq := api.StorageVolumePut{
 		Description: plan.Description.ValueString(),
@@ -393,9 +465,13 @@ func (r StorageVolumeResource) SyncState(ctx context.Context, tfState *tfsdk.Sta
 	}
 
 	combinedComputedKeys := append(inheritedPoolVolumeKeys, m.ComputedKeys()...)
-	stateConfig := common.StripConfig(vol.Config, m.Config, combinedComputedKeys)
+	// Labels are excluded from the config.
+	stateConfig := common.StripConfig(common.StripLabels(vol.Config), m.Config, combinedComputedKeys)
+
+	config, diags := common.ToConfigValueMapType(ctx, stateConfig, m.Config)
+	respDiags.Append(diags...)
 
-	config, diags := common.ToConfigMapType(ctx, stateConfig, m.Config)
+	labels, diags := common.ToLabelsMapType(ctx, vol.Config, m.Labels)
 	respDiags.Append(diags...)
 
 	m.Name = types.StringValue(vol.Name)
@@ -404,6 +480,7 @@ func (r StorageVolumeResource) SyncState(ctx context.Context, tfState *tfsdk.Sta
 	m.Description = types.StringValue(vol.Description)
 	m.ContentType = types.StringValue(vol.ContentType)
 	m.Config = config
+	m.Labels = labels
 
 	m.Target = types.StringValue("")
 	if server.IsClustered() || vol.Location != "none" {
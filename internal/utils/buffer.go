@@ -51,6 +51,46 @@ func (b bufferCloser) String() string {
 	return b.buf.String()
 }
 
+type limitedBufferCloser struct {
+	bufferCloser
+	limit int64
+}
+
+// NewLimitedBufferCloser returns a buffer that stores at most limit bytes
+// and silently discards the rest. If limit is not positive, the buffer is
+// not limited.
+func NewLimitedBufferCloser(limit int64) Buffer {
+	if limit <= 0 {
+		return NewBufferCloser()
+	}
+
+	return limitedBufferCloser{
+		bufferCloser: bufferCloser{
+			buf: &bytes.Buffer{},
+			mux: &sync.RWMutex{},
+		},
+		limit: limit,
+	}
+}
+
+// Write writes bytes to the buffer until the limit is reached. It always
+// reports all bytes as written to not interrupt the writer.
+func (b limitedBufferCloser) Write(p []byte) (int, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	remaining := b.limit - int64(b.buf.Len())
+	if remaining > 0 {
+		if int64(len(p)) > remaining {
+			_, _ = b.buf.Write(p[:remaining])
+		} else {
+			_, _ = b.buf.Write(p)
+		}
+	}
+
+	return len(p), nil
+}
+
 type discardCloser struct{}
 
 // NewDiscardCloser returns a buffer that simply discards everything.
@@ -108,3 +108,33 @@ func ToPrettyJSON(v any) string {
 	bytes, _ := json.MarshalIndent(v, "", "    ")
 	return string(bytes)
 }
+
+// EditDistance returns the Levenshtein distance between two strings, which
+// is the minimum number of single-character edits required to change one
+// string into the other.
+func EditDistance(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}